@@ -0,0 +1,281 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/detect"
+	"clipbox/frontend"
+	"clipbox/paste"
+	"clipbox/snippet"
+	"clipbox/transform"
+	"clipbox/utils"
+)
+
+// snippetDataPrefix marks a rofi placeholder prompt in ROFI_DATA,
+// followed by "<id>:<paste 0|1>:<url-encoded answers>"
+const snippetDataPrefix = "snippet:"
+
+// selectEntry copies an entry to the clipboard.
+// If pasteNow is true or auto_paste is enabled, the entry is also pasted into the focused window.
+// Placeholders are expanded first, answers holds the {{prompt:...}} values entered so far.
+func selectEntry(b backend, id int, pasteNow bool, answers url.Values) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	record, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+
+	content := record.Content
+	if cfg.ExpandTemplates && !record.TemplateDisabled && snippet.HasPlaceholders(content) {
+		prompt := utils.Prompt
+		if os.Getenv("ROFI_RETV") != "" {
+			// rofi is still open and a second instance can't start,
+			// so missing answers are asked in the rofi window itself
+			for _, label := range snippet.PromptLabels(content) {
+				if !answers.Has(label) {
+					writeSnippetPrompt(os.Stdout, id, pasteNow, answers, label)
+					return nil
+				}
+			}
+			prompt = func(label string) (string, error) {
+				return answers.Get(label), nil
+			}
+		}
+
+		sources := snippet.Sources{
+			Now:       time.Now,
+			Clipboard: utils.ReadClipboard,
+			Prompt:    prompt,
+		}
+		// Environment variables are only filled into pinned entries, so copied text
+		// containing {{env:...}} cannot leak tokens onto the clipboard
+		if record.Pinned {
+			sources.Env = os.Getenv
+		}
+		content, err = snippet.Expand(content, sources)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := utils.CopyToClipboardType(content, copyType(record, content), cfg.CopyToPrimary); err != nil {
+		return err
+	}
+
+	if pasteNow || cfg.AutoPaste {
+		return paste.Schedule(content, cfg)
+	}
+	return nil
+}
+
+// copyType returns the MIME type content of record is copied as, empty to let wl-copy detect it.
+// Files copied in a file manager are offered as a file list again, so they can be pasted as files.
+// file:// URIs copied as text stay text.
+func copyType(record *database.Record, content []byte) string {
+	if _, ok := detect.ParseFileList(content); ok && record.MimeType == detect.FileListType {
+		return detect.FileListType
+	}
+	return ""
+}
+
+// pickEntry shows the current buffer in a launcher and selects the chosen entry.
+// An empty name uses the frontend from config, frontend_command replaces its command line.
+func pickEntry(b backend, name string, app string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = cfg.Frontend
+	}
+	f, err := frontend.Get(name)
+	if err != nil {
+		return err
+	}
+
+	listing, err := b.Listing(0, app)
+	if err != nil {
+		return err
+	}
+
+	var input bytes.Buffer
+	f.Menu(&input, listing, cfg)
+
+	var cmd *exec.Cmd
+	if cfg.FrontendCommand != "" {
+		cmd = exec.Command("sh", "-c", cfg.FrontendCommand)
+		cmd.Env = append(os.Environ(), "CLIPBOX_PROMPT="+listing.BufferName)
+	} else {
+		args := f.Command(listing.BufferName)
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Cancelled
+			return nil
+		}
+		return fmt.Errorf("failed to run %s: %w", cmd.Args[0], err)
+	}
+
+	if strings.TrimSpace(string(output)) == "" {
+		// Closed without choosing an entry
+		return nil
+	}
+	ids, err := frontend.ParseSelection(string(output))
+	if err != nil {
+		return fmt.Errorf("failed to read selection from %s: %w", cmd.Args[0], err)
+	}
+	return selectEntry(b, ids[0], false, url.Values{})
+}
+
+// writeSnippetPrompt asks for a placeholder value in rofi.
+// The typed text comes back as custom input (ROFI_RETV=2) with the state in ROFI_DATA.
+func writeSnippetPrompt(w io.Writer, id int, pasteNow bool, answers url.Values, label string) {
+	pasteFlag := 0
+	if pasteNow {
+		pasteFlag = 1
+	}
+	fmt.Fprintf(w, "\x00prompt\x1f%s\n", label)
+	fmt.Fprint(w, "\x00no-custom\x1ffalse\n")
+	fmt.Fprintf(w, "\x00message\x1fType the value for %s and press Enter\n", utils.PangoReplacer.Replace(label))
+	fmt.Fprintf(w, "\x00data\x1f%s%d:%d:%s\n", snippetDataPrefix, id, pasteFlag, answers.Encode())
+}
+
+// continueSnippetPrompt handles a value typed into a placeholder prompt.
+// Returns false if rofiData doesn't belong to a prompt.
+func continueSnippetPrompt(b backend, rofiData string, input string) (bool, error) {
+	state, ok := strings.CutPrefix(rofiData, snippetDataPrefix)
+	if !ok {
+		return false, nil
+	}
+	parts := strings.SplitN(state, ":", 3)
+	if len(parts) != 3 {
+		return false, nil
+	}
+	id, err := strconv.Atoi(parts[0])
+	if err != nil || id <= 0 {
+		return false, nil
+	}
+	answers, err := url.ParseQuery(parts[2])
+	if err != nil {
+		return false, nil
+	}
+
+	record, err := b.Get(id)
+	if err != nil {
+		return true, err
+	}
+	for _, label := range snippet.PromptLabels(record.Content) {
+		if !answers.Has(label) {
+			answers.Set(label, input)
+			break
+		}
+	}
+	return true, selectEntry(b, id, parts[1] == "1", answers)
+}
+
+// transformDataPrefix marks the rofi transform submenu in ROFI_DATA, followed by the entry ID
+const transformDataPrefix = "transform:"
+
+// loadTransforms registers the transforms that depend on the config, such as the time_zone date formats.
+func loadTransforms() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	transform.RegisterTimeZones(cfg.TimeZones)
+	return nil
+}
+
+// writeTransformMenu prints the transforms available for an entry as a rofi submenu.
+func writeTransformMenu(w io.Writer, b backend, id int) error {
+	if err := loadTransforms(); err != nil {
+		return err
+	}
+
+	record, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(w, "\x00use-hot-keys\x1ftrue\n")
+	fmt.Fprint(w, "\x00markup-rows\x1ftrue\n")
+	fmt.Fprint(w, "\x00prompt\x1fTransform\n")
+	fmt.Fprintf(w, "\x00data\x1f%s%d\n", transformDataPrefix, id)
+
+	available := transform.Available(record.Content)
+	for _, t := range available {
+		fmt.Fprintf(w, "%s <span alpha='50%%'>%s</span>\x00info\x1f%s\n",
+			t.Name, utils.PangoReplacer.Replace(t.Description), t.Name)
+	}
+	if len(available) == 0 {
+		fmt.Fprint(w, " (No transforms for this entry)\x00info\x1f0\n")
+	}
+	return nil
+}
+
+// transformMenuEntryID returns the entry ID stored in ROFI_DATA by writeTransformMenu.
+func transformMenuEntryID(rofiData string) (int, bool) {
+	idStr, ok := strings.CutPrefix(rofiData, transformDataPrefix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// runTransform applies a transform to an entry and copies the result to the clipboard.
+// The result is also stored as a new entry if transform_store is enabled.
+func runTransform(b backend, name string, id int) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	transform.RegisterTimeZones(cfg.TimeZones)
+
+	record, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := transform.Apply(name, record.Content)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.CopyToClipboard(result, cfg.CopyToPrimary); err != nil {
+		return err
+	}
+
+	if cfg.TransformStore {
+		if err := b.Store(bytes.NewReader(result), utils.SourceClipboard, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"testing"
+
+	"clipbox/database"
+)
+
+func TestCopyType(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		mimeType string
+		want     string
+	}{
+		{"copied files", "file:///home/user/notes.txt", "text/uri-list", "text/uri-list"},
+		{"file uri copied as text", "file:///home/user/notes.txt", "", ""},
+		{"file list rewritten to text", "notes.txt", "text/uri-list", ""},
+		{"text", "hello", "", ""},
+	}
+	for _, tt := range tests {
+		record := &database.Record{Content: []byte(tt.content), MimeType: tt.mimeType}
+		if got := copyType(record, record.Content); got != tt.want {
+			t.Errorf("%s: copyType = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
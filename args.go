@@ -0,0 +1,18 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// removeFlag removes every occurrence of flag from args and reports whether it was present.
+func removeFlag(args []string, flag string) ([]string, bool) {
+	result := args[:0:0]
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result, found
+}
@@ -0,0 +1,201 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/detect"
+	"clipbox/export"
+	"clipbox/frontend"
+	"clipbox/ipc"
+)
+
+// backend performs clipbox operations either directly on the database
+// or through a running clipbox server.
+type backend interface {
+	Listing(limit int, app string) (*database.Listing, error)
+	Get(id int) (*database.Record, error)
+	Records(ids []int) ([]*database.Record, error)
+	Store(r io.Reader, source string, bufferID int) error
+	TogglePin(id int) error
+	SetPinned(id int, pinned bool) error
+	Move(id int, bufferID int) error
+	ToggleTemplate(id int) error
+	Delete(id int) error
+	SwitchBuffer(bufferID int) error
+	SwitchToNextBuffer() error
+	SwitchToPreviousBuffer() error
+}
+
+// localBackend calls the database package directly.
+type localBackend struct{}
+
+func (localBackend) Listing(limit int, app string) (*database.Listing, error) {
+	return database.LoadListing(limit, app)
+}
+
+func (localBackend) Get(id int) (*database.Record, error) { return database.GetRecord(id) }
+func (localBackend) Records(ids []int) ([]*database.Record, error) {
+	return database.GetRecords(ids)
+}
+func (localBackend) Store(r io.Reader, source string, bufferID int) error {
+	return database.Store(r, source, bufferID)
+}
+func (localBackend) TogglePin(id int) error              { return database.TogglePin(id) }
+func (localBackend) SetPinned(id int, pinned bool) error { return database.SetPinned(id, pinned) }
+func (localBackend) Move(id int, bufferID int) error     { return database.MoveEntry(id, bufferID) }
+func (localBackend) ToggleTemplate(id int) error         { return database.ToggleTemplate(id) }
+func (localBackend) Delete(id int) error                 { return database.DeleteEntry(id) }
+func (localBackend) SwitchBuffer(bufferID int) error     { return database.SwitchBuffer(bufferID) }
+func (localBackend) SwitchToNextBuffer() error           { return database.SwitchToNextBuffer() }
+func (localBackend) SwitchToPreviousBuffer() error       { return database.SwitchToPreviousBuffer() }
+
+// selectBackend returns the socket client when remote is requested and the
+// server socket exists, and the local database otherwise.
+func selectBackend(remote bool) backend {
+	if remote {
+		if client := ipc.NewClient(); client.Available() {
+			return client
+		}
+	}
+	return localBackend{}
+}
+
+// listRofi prints the current buffer in rofi script mode format,
+// which is the reply to rofi key bindings regardless of the configured frontend.
+func listRofi(b backend, limit int, app string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	listing, err := b.Listing(limit, app)
+	if err != nil {
+		return err
+	}
+	frontend.WriteRofi(os.Stdout, listing, cfg)
+	return nil
+}
+
+// listFrontend prints the current buffer in the format of the named frontend.
+// An empty name uses the frontend from config.
+func listFrontend(b backend, name string, limit int, app string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = cfg.Frontend
+	}
+	f, err := frontend.Get(name)
+	if err != nil {
+		return err
+	}
+	listing, err := b.Listing(limit, app)
+	if err != nil {
+		return err
+	}
+	f.Render(os.Stdout, listing, cfg)
+	return nil
+}
+
+// listExport prints the current buffer as JSON or TSV for scripts.
+func listExport(b backend, format string, limit int, app string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	listing, err := b.Listing(limit, app)
+	if err != nil {
+		return err
+	}
+
+	rows := frontend.Rows(listing)
+	ids := make([]int, len(rows))
+	for i, e := range rows {
+		ids[i] = e.ID
+	}
+	records, err := b.Records(ids)
+	if err != nil {
+		return err
+	}
+
+	var items []export.Item
+	for _, record := range records {
+		items = append(items, export.NewItem(record, export.PreviewText(record, cfg), cfg))
+	}
+
+	if format == "tsv" {
+		export.WriteTSV(os.Stdout, items)
+		return nil
+	}
+	return export.WriteListJSON(os.Stdout, listing, items)
+}
+
+// saveEntry writes the content of an entry to a file and prints its path.
+// A directory gets a file named clipbox-ID, and a path without an extension gets the extension of the content format.
+func saveEntry(b backend, id int, path string, force bool) error {
+	record, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	bufferCfg := cfg.ForBuffer(record.Buffer)
+	classification := detect.NewClassifier(bufferCfg.PasswordIgnorePatterns, bufferCfg.SecretThreshold).Classify(record.Content)
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, fmt.Sprintf("clipbox-%d", id))
+	}
+	if ext := classification.Extension(); filepath.Ext(path) == "" && ext != "" {
+		path += "." + ext
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+		}
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if _, err := f.Write(record.Content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Println(path)
+	return nil
+}
+
+// getEntry prints the content of an entry, or the entry with its metadata as JSON.
+func getEntry(b backend, id int, format string) error {
+	record, err := b.Get(id)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+		return export.WriteGetJSON(os.Stdout, record, cfg)
+	}
+	_, err = os.Stdout.Write(record.Content)
+	return err
+}
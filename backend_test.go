@@ -0,0 +1,94 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"clipbox/database"
+)
+
+// recordBackend serves records from memory, other operations are not implemented
+type recordBackend struct {
+	backend
+	records map[int]*database.Record
+}
+
+func (b recordBackend) Get(id int) (*database.Record, error) {
+	if record, ok := b.records[id]; ok {
+		return record, nil
+	}
+	return nil, fmt.Errorf("entry %d not found", id)
+}
+
+func TestSaveEntry(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	contents := map[int]string{
+		1: "plain text",
+		2: pngData.String(),
+		3: `{"name": "clipbox", "version": 1}`,
+		4: "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n",
+		5: "\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00",
+		6: "\x00\x01\x02\x03\xfe\xff\x00\x10",
+		7: "#!/usr/bin/env python3\nimport sys\nprint(sys.argv)\n",
+	}
+	b := recordBackend{records: map[int]*database.Record{}}
+	for id, content := range contents {
+		b.records[id] = &database.Record{ID: id, Buffer: 1, Content: []byte(content)}
+	}
+
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		id   int
+		path string
+		want string
+	}{
+		{"text into a directory", 1, dir, "clipbox-1.txt"},
+		{"image", 2, filepath.Join(dir, "screenshot"), "screenshot.png"},
+		{"json", 3, filepath.Join(dir, "data"), "data.json"},
+		{"pdf", 4, filepath.Join(dir, "report"), "report.pdf"},
+		{"executable without extension", 5, dir, "clipbox-5"},
+		{"unknown binary", 6, dir, "clipbox-6.bin"},
+		{"code", 7, dir, "clipbox-7.py"},
+		{"extension kept", 2, filepath.Join(dir, "image.dat"), "image.dat"},
+	}
+	for _, tt := range tests {
+		if err := saveEntry(b, tt.id, tt.path, false); err != nil {
+			t.Errorf("%s: saveEntry: %v", tt.name, err)
+			continue
+		}
+		got, err := os.ReadFile(filepath.Join(dir, tt.want))
+		if err != nil || string(got) != contents[tt.id] {
+			t.Errorf("%s: %s = %q, %v, want the content of entry %d", tt.name, tt.want, got, err, tt.id)
+		}
+	}
+
+	// Existing files are only replaced with force
+	path := filepath.Join(dir, "clipbox-1.txt")
+	b.records[1].Content = []byte("changed text")
+	if err := saveEntry(b, 1, dir, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("saveEntry over an existing file error = %v, want already exists", err)
+	}
+	if err := saveEntry(b, 1, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "changed text" {
+		t.Errorf("%s after --force = %q, want changed text", path, got)
+	}
+	if err := saveEntry(b, 99, dir, false); err == nil {
+		t.Error("saveEntry of a missing entry succeeded")
+	}
+}
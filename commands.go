@@ -0,0 +1,565 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/ipc"
+	"clipbox/maintenance"
+	"clipbox/paste"
+	"clipbox/transform"
+	"clipbox/tui"
+	"clipbox/utils"
+)
+
+// errUsage is returned by commands after printing their usage for invalid arguments
+var errUsage = errors.New("invalid usage")
+
+// command is a clipbox subcommand.
+// Every command is also available as --name, the syntax of earlier versions.
+type command struct {
+	name     string
+	synopsis string
+	summary  string
+	run      func(b backend, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"list", "[LIMIT] [--limit N] [--app NAME] [--json | --format json|tsv] [--frontend NAME]",
+		"Print the current buffer for a launcher or as JSON/TSV.", runList},
+	{"pick", "[--app NAME] [--frontend NAME]",
+		"Show the current buffer in a launcher and copy the chosen entry.", runPick},
+	{"tui", "",
+		"Browse the history in a full-screen terminal interface.", runTUI},
+	{"get", "ID | --nth N [--json]",
+		"Print the content of an entry, or the entry with its metadata as JSON.", runGet},
+	{"copy", "ID | --nth N [--paste]",
+		"Copy an entry to the clipboard.", runCopy},
+	{"save", "ID PATH [--force]",
+		"Save an entry to a file, adding the extension of its format if PATH has none.", runSave},
+	{"pin", "ID...",
+		"Pin entries.", runPin},
+	{"unpin", "ID...",
+		"Unpin entries.", runUnpin},
+	{"delete", "ID...",
+		"Delete entries.", runDelete},
+	{"buffer", "[switch N | next | prev]",
+		"Switch the current buffer, or print it without arguments.", runBuffer},
+	{"store", "[--primary] [--buffer N]",
+		"Store stdin as a new entry.", runStore},
+	{"transform", "NAME ID",
+		"Copy an entry with a transform applied.", runTransformCommand},
+	{"toggle-template", "ID",
+		"Turn placeholder expansion off or on for an entry.", runToggleTemplate},
+	{"multi-select", "",
+		"Select several entries in rofi and merge, pin, move or delete them.", runMultiSelectCommand},
+	{"multi", "merge|pin|delete|move [BUFFER]",
+		"Apply an action to the entries of the launcher rows read from stdin.", runMultiCommand},
+	{"check-config", "",
+		"Report problems in the config file and show the effective settings.", runCheckConfig},
+	{"serve", "",
+		"Serve clipbox operations on a Unix socket for --remote.", runServe},
+	{"rebuild-previews", "[--if-stale]",
+		"Regenerate the previews of all entries.", runRebuildPreviews},
+	{"vacuum", "",
+		"Compact the database file.", runVacuum},
+}
+
+// findCommand returns the command with the given name or --name alias.
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if name == cmd.name || name == "--"+cmd.name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// newFlagSet returns the flag set of a command, printing its usage on errors.
+func newFlagSet(cmd command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: clipbox %s %s\n\n%s\n", cmd.name, cmd.synopsis, cmd.summary)
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(os.Stderr, "\nFlags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// printUsage prints the list of commands.
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: clipbox [--remote] COMMAND [ARGS]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-17s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nWithout a command the current buffer is printed for rofi.\n")
+	fmt.Fprintf(os.Stderr, "Run 'clipbox COMMAND --help' for the arguments of a command.\n")
+}
+
+// parseFlags parses flags placed anywhere between the positional arguments.
+// All arguments after "--" are positional. Returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			// The flag package already printed the error with the usage
+			return nil, errUsage
+		}
+		rest := fs.Args()
+		// fs.Parse drops the "--" that ends the flags
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// usageErrorf prints an error with the usage of the command and returns errUsage.
+func usageErrorf(fs *flag.FlagSet, format string, a ...any) error {
+	fmt.Fprintf(os.Stderr, "Error: %s\n\n", fmt.Sprintf(format, a...))
+	fs.Usage()
+	return errUsage
+}
+
+// parseIDs parses entry IDs given as arguments.
+func parseIDs(fs *flag.FlagSet, args []string) ([]int, error) {
+	if len(args) == 0 {
+		return nil, usageErrorf(fs, "missing entry ID")
+	}
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, usageErrorf(fs, "invalid id: %s", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// resolveEntry returns the entry ID given as the only argument, or the nth newest entry
+// of the current buffer if nth is set.
+func resolveEntry(b backend, fs *flag.FlagSet, args []string, nth int) (int, error) {
+	if nth != 0 {
+		if len(args) > 0 {
+			return 0, usageErrorf(fs, "--nth can't be combined with an ID")
+		}
+		if nth < 0 {
+			return 0, usageErrorf(fs, "invalid --nth: %d", nth)
+		}
+		listing, err := b.Listing(nth, "")
+		if err != nil {
+			return 0, err
+		}
+		if nth > len(listing.Entries) {
+			return 0, fmt.Errorf("buffer %d has only %d entries", listing.Buffer, len(listing.Entries))
+		}
+		return listing.Entries[nth-1].ID, nil
+	}
+
+	if len(args) != 1 {
+		return 0, usageErrorf(fs, "expected one entry ID")
+	}
+	ids, err := parseIDs(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// formatFlags defines --json and --format and returns a function resolving the chosen format.
+func formatFlags(fs *flag.FlagSet, formats string) func() (string, error) {
+	asJSON := fs.Bool("json", false, "print JSON, same as --format json")
+	format := fs.String("format", "", "output format: "+formats)
+	return func() (string, error) {
+		if *asJSON {
+			if *format != "" && *format != "json" {
+				return "", usageErrorf(fs, "--json conflicts with --format %s", *format)
+			}
+			return "json", nil
+		}
+		switch *format {
+		case "", "json", "tsv":
+			return *format, nil
+		default:
+			return "", usageErrorf(fs, "unknown format: %s (available: %s)", *format, formats)
+		}
+	}
+}
+
+func runList(b backend, fs *flag.FlagSet, args []string) error {
+	limit := fs.Int("limit", 0, "maximum number of entries (default: limit from config)")
+	app := fs.String("app", "", "only entries copied from this application")
+	frontendName := fs.String("frontend", "", "launcher format: rofi, fuzzel, wofi, bemenu, dmenu or fzf (default: frontend from config)")
+	outputFormat := formatFlags(fs, "json, tsv")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+
+	// The limit may also be given as the first argument
+	switch len(args) {
+	case 0:
+	case 1:
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return usageErrorf(fs, "invalid limit: %s", args[0])
+		}
+		*limit = n
+	default:
+		return usageErrorf(fs, "unexpected arguments: %v", args[1:])
+	}
+	if *limit < 0 {
+		return usageErrorf(fs, "invalid limit: %d", *limit)
+	}
+
+	if format != "" {
+		return listExport(b, format, *limit, *app)
+	}
+	return listFrontend(b, *frontendName, *limit, *app)
+}
+
+func runPick(b backend, fs *flag.FlagSet, args []string) error {
+	app := fs.String("app", "", "only entries copied from this application")
+	frontendName := fs.String("frontend", "", "launcher: rofi, fuzzel, wofi, bemenu, dmenu or fzf (default: frontend from config)")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usageErrorf(fs, "unexpected arguments: %v", args)
+	}
+	return pickEntry(b, *frontendName, *app)
+}
+
+func runTUI(b backend, fs *flag.FlagSet, args []string) error {
+	if err := noArguments(fs, args); err != nil {
+		return err
+	}
+	id, err := tui.Run(b, os.Stdin, os.Stdout)
+	if err != nil || id == 0 {
+		return err
+	}
+	return selectEntry(b, id, false, url.Values{})
+}
+
+func runGet(b backend, fs *flag.FlagSet, args []string) error {
+	nth := fs.Int("nth", 0, "the Nth newest entry of the current buffer instead of an ID")
+	outputFormat := formatFlags(fs, "json")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	format, err := outputFormat()
+	if err != nil {
+		return err
+	}
+	if format == "tsv" {
+		return usageErrorf(fs, "get supports only --json")
+	}
+	id, err := resolveEntry(b, fs, args, *nth)
+	if err != nil {
+		return err
+	}
+	return getEntry(b, id, format)
+}
+
+func runCopy(b backend, fs *flag.FlagSet, args []string) error {
+	nth := fs.Int("nth", 0, "the Nth newest entry of the current buffer instead of an ID")
+	pasteNow := fs.Bool("paste", false, "also paste the entry into the focused window")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := resolveEntry(b, fs, args, *nth)
+	if err != nil {
+		return err
+	}
+	return selectEntry(b, id, *pasteNow, url.Values{})
+}
+
+func runSave(b backend, fs *flag.FlagSet, args []string) error {
+	force := fs.Bool("force", false, "overwrite an existing file")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usageErrorf(fs, "expected an entry ID and a path")
+	}
+	ids, err := parseIDs(fs, args[:1])
+	if err != nil {
+		return err
+	}
+	return saveEntry(b, ids[0], args[1], *force)
+}
+
+func runPin(b backend, fs *flag.FlagSet, args []string) error {
+	return setPinned(b, fs, args, true)
+}
+
+func runUnpin(b backend, fs *flag.FlagSet, args []string) error {
+	return setPinned(b, fs, args, false)
+}
+
+func setPinned(b backend, fs *flag.FlagSet, args []string, pinned bool) error {
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs, args)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := b.SetPinned(id, pinned); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runDelete(b backend, fs *flag.FlagSet, args []string) error {
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs, args)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := b.Delete(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runBuffer(b backend, fs *flag.FlagSet, args []string) error {
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		listing, err := b.Listing(1, "")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d\t%s\n", listing.Buffer, listing.BufferName)
+		return nil
+	}
+
+	switch args[0] {
+	case "next":
+		if len(args) == 1 {
+			return b.SwitchToNextBuffer()
+		}
+	case "prev":
+		if len(args) == 1 {
+			return b.SwitchToPreviousBuffer()
+		}
+	case "switch":
+		if len(args) == 2 {
+			bufferID, err := strconv.Atoi(args[1])
+			if err != nil || bufferID < 1 || bufferID > 5 {
+				return usageErrorf(fs, "invalid buffer: %s (must be 1-5)", args[1])
+			}
+			return b.SwitchBuffer(bufferID)
+		}
+	default:
+		return usageErrorf(fs, "unknown action: %s", args[0])
+	}
+	return usageErrorf(fs, "wrong number of arguments for %s", args[0])
+}
+
+func runStore(b backend, fs *flag.FlagSet, args []string) error {
+	primary := fs.Bool("primary", false, "the content comes from the primary selection")
+	bufferID := fs.Int("buffer", 0, "store to buffer N (1-5) instead of the current buffer")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usageErrorf(fs, "unexpected arguments: %v", args)
+	}
+	if *bufferID < 0 || *bufferID > 5 {
+		return usageErrorf(fs, "invalid buffer: %d (must be 1-5)", *bufferID)
+	}
+	source := utils.SourceClipboard
+	if *primary {
+		source = utils.SourcePrimary
+	}
+	return b.Store(os.Stdin, source, *bufferID)
+}
+
+func runTransformCommand(b backend, fs *flag.FlagSet, args []string) error {
+	if err := loadTransforms(); err != nil {
+		return err
+	}
+	defaultUsage := fs.Usage
+	fs.Usage = func() {
+		defaultUsage()
+		fmt.Fprintf(os.Stderr, "\nTransforms:\n")
+		for _, t := range transform.All() {
+			fmt.Fprintf(os.Stderr, "  %-14s %s\n", t.Name, t.Description)
+		}
+	}
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return usageErrorf(fs, "expected a transform name and an entry ID")
+	}
+	ids, err := parseIDs(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	return runTransform(b, args[0], ids[0])
+}
+
+func runToggleTemplate(b backend, fs *flag.FlagSet, args []string) error {
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usageErrorf(fs, "expected one entry ID")
+	}
+	ids, err := parseIDs(fs, args)
+	if err != nil {
+		return err
+	}
+	return b.ToggleTemplate(ids[0])
+}
+
+func runMultiSelectCommand(b backend, fs *flag.FlagSet, args []string) error {
+	if err := noArguments(fs, args); err != nil {
+		return err
+	}
+	return runMultiSelect(b)
+}
+
+func runMultiCommand(b backend, fs *flag.FlagSet, args []string) error {
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	action, arg, err := parseMultiArgs(args)
+	if err != nil {
+		return usageErrorf(fs, "%v", err)
+	}
+	return runMultiFromReader(b, action, arg, os.Stdin)
+}
+
+func runServe(b backend, fs *flag.FlagSet, args []string) error {
+	if err := noArguments(fs, args); err != nil {
+		return err
+	}
+	return ipc.Serve(ipc.SocketPath())
+}
+
+func runRebuildPreviews(b backend, fs *flag.FlagSet, args []string) error {
+	ifStale := fs.Bool("if-stale", false, "only rebuild if the preview settings changed since the last rebuild")
+	if err := noArguments(fs, args); err != nil {
+		return err
+	}
+	return maintenance.RebuildAllPreviews(*ifStale)
+}
+
+func runVacuum(b backend, fs *flag.FlagSet, args []string) error {
+	if err := noArguments(fs, args); err != nil {
+		return err
+	}
+	return maintenance.VacuumDB()
+}
+
+func runCheckConfig(b backend, fs *flag.FlagSet, args []string) error {
+	if err := noArguments(fs, args); err != nil {
+		return err
+	}
+	report, err := config.Check()
+	if err != nil {
+		return err
+	}
+
+	if !report.Exists {
+		fmt.Printf("%s: not found, using defaults\n", report.Path)
+		return nil
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, d := range report.Diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+		if d.Severity == config.SeverityError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+
+	fmt.Printf("Settings in %s:\n", report.Path)
+	for _, setting := range report.Settings {
+		key, fallback := setting.Key, "default"
+		if setting.Buffer > 0 {
+			key, fallback = fmt.Sprintf("[buffer.%d] %s", setting.Buffer, setting.Key), "global"
+		}
+		fmt.Printf("  %-26s = %s (%s: %s, line %d)\n", key, setting.Value, fallback, setting.Default, setting.Line)
+	}
+	fmt.Printf("%d errors, %d warnings\n", errorCount, warningCount)
+
+	if report.HasErrors() {
+		return fmt.Errorf("%s has %d errors", report.Path, errorCount)
+	}
+	return nil
+}
+
+// noArguments parses the flags of a command that takes no arguments.
+func noArguments(fs *flag.FlagSet, args []string) error {
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return usageErrorf(fs, "unexpected arguments: %v", args)
+	}
+	return nil
+}
+
+// runPasteDeferred pastes stdin after the launcher closes, started by paste.Schedule.
+func runPasteDeferred() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	content, err := database.ReadContent(os.Stdin)
+	if err != nil {
+		return err
+	}
+	return paste.RunDeferred(content, cfg)
+}
@@ -0,0 +1,50 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args       string
+		positional []string
+		buffer     int
+		json       bool
+		wantErr    bool
+	}{
+		{"", nil, 0, false, false},
+		{"5", []string{"5"}, 0, false, false},
+		{"5 --buffer 2 out.txt", []string{"5", "out.txt"}, 2, false, false},
+		{"--json 5 6", []string{"5", "6"}, 0, true, false},
+		{"5 -- -x -y", []string{"5", "-x", "-y"}, 0, false, false},
+		{"-- --json", []string{"--json"}, 0, false, false},
+		{"--buffer 3 -- 5 --json", []string{"5", "--json"}, 3, false, false},
+		{"5 --bogus", nil, 0, false, true},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		buffer := fs.Int("buffer", 0, "")
+		json := fs.Bool("json", false, "")
+
+		positional, err := parseFlags(fs, strings.Fields(tt.args))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFlags(%q) error = %v, want error %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !slices.Equal(positional, tt.positional) || *buffer != tt.buffer || *json != tt.json {
+			t.Errorf("parseFlags(%q) = %q, buffer %d, json %v, want %q, buffer %d, json %v",
+				tt.args, positional, *buffer, *json, tt.positional, tt.buffer, tt.json)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package config
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Severity of a config diagnostic
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found while parsing the config file
+type Diagnostic struct {
+	File     string
+	Line     int
+	Key      string
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", d.File, d.Line, d.Severity, d.Message)
+}
+
+// Setting is a key set in the config file with its effective and default value.
+// For keys in [buffer.N] sections the default is the global value.
+type Setting struct {
+	Key     string
+	Buffer  int // Buffer of the [buffer.N] section, 0 for global keys
+	Line    int // Line of the last occurrence
+	Value   string
+	Default string
+}
+
+// Report is the result of checking the config file
+type Report struct {
+	Path        string
+	Exists      bool
+	Diagnostics []Diagnostic
+	Settings    []Setting // In order of first appearance
+}
+
+// HasErrors reports whether any diagnostic is an error
+func (r *Report) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Check parses the config file and reports all problems and the effective settings.
+func Check() (*Report, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+	report := &Report{Path: configPath}
+
+	file, err := os.Open(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return nil, fmt.Errorf("failed to open config: %w", err)
+	}
+	defer file.Close()
+	report.Exists = true
+
+	cfg := defaultConfig()
+	defaults := defaultConfig()
+	lines, diagnostics, err := parse(file, configPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	report.Diagnostics = diagnostics
+
+	for _, k := range lines.order {
+		s, _ := findSetting(k.key)
+		setting := Setting{
+			Key:     k.key,
+			Buffer:  k.buffer,
+			Line:    lines.last[k],
+			Value:   s.value(cfg),
+			Default: s.value(defaults),
+		}
+		if k.buffer > 0 {
+			setting.Value = s.value(cfg.ForBuffer(k.buffer))
+			setting.Default = s.value(cfg)
+		}
+		report.Settings = append(report.Settings, setting)
+	}
+	return report, nil
+}
+
+// sectionKey is a key in the global section (buffer 0) or a [buffer.N] section
+type sectionKey struct {
+	buffer int
+	key    string
+}
+
+// keyLines records where known keys were set
+type keyLines struct {
+	order []sectionKey
+	last  map[sectionKey]int
+}
+
+// parse reads config lines into cfg and collects diagnostics for every problem.
+// Invalid values keep the previous value of their setting.
+func parse(r io.Reader, file string, cfg *Config) (keyLines, []Diagnostic, error) {
+	lines := keyLines{last: map[sectionKey]int{}}
+	var diagnostics []Diagnostic
+	report := func(line int, key string, severity Severity, format string, a ...any) {
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     file,
+			Line:     line,
+			Key:      key,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	section := 0 // 0 = global keys, -1 = keys of an invalid section
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			name, ok := strings.CutSuffix(line[1:], "]")
+			bufferID, valid := parseSection(name)
+			if !ok || !valid {
+				report(lineNumber, "", SeverityError, "unknown section %s, expected [buffer.1] to [buffer.5]", line)
+				// Skip the keys of the section instead of applying them globally
+				section = -1
+				continue
+			}
+			section = bufferID
+			continue
+		}
+		if section < 0 {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			report(lineNumber, "", SeverityError, "expected key=value, got %q", line)
+			continue
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+
+		s, ok := findSetting(key)
+		if !ok {
+			if suggestion := closestKey(key); suggestion != "" {
+				report(lineNumber, key, SeverityError, "unknown key %q (did you mean %q?)", key, suggestion)
+			} else {
+				report(lineNumber, key, SeverityError, "unknown key %q", key)
+			}
+			continue
+		}
+		if section > 0 && !s.perBuffer {
+			report(lineNumber, key, SeverityError, "%s can't be set in [buffer.%d]", key, section)
+			continue
+		}
+
+		k := sectionKey{buffer: section, key: key}
+		if previous, seen := lines.last[k]; seen && !s.repeatable {
+			report(lineNumber, key, SeverityWarning, "%s overrides the value set on line %d", key, previous)
+		}
+		if _, seen := lines.last[k]; !seen {
+			lines.order = append(lines.order, k)
+		}
+		lines.last[k] = lineNumber
+
+		// Section values are validated here and applied by ForBuffer
+		target := cfg
+		if section > 0 {
+			scratch := *cfg
+			target = &scratch
+		}
+		if err := s.parse(target, value); err != nil {
+			var w warning
+			if errors.As(err, &w) {
+				report(lineNumber, key, SeverityWarning, "%s: %v", key, err)
+			} else {
+				report(lineNumber, key, SeverityError, "%s: invalid value %q: %v", key, value, err)
+				continue
+			}
+		}
+		if section > 0 {
+			cfg.bufferOverrides[section-1] = append(cfg.bufferOverrides[section-1], override{key: key, value: value})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return lines, diagnostics, fmt.Errorf("error reading config: %w", err)
+	}
+	return lines, diagnostics, nil
+}
+
+// parseSection returns the buffer of a "buffer.N" section name
+func parseSection(name string) (int, bool) {
+	number, ok := strings.CutPrefix(name, "buffer.")
+	if !ok {
+		return 0, false
+	}
+	bufferID, err := strconv.Atoi(number)
+	if err != nil || bufferID < 1 || bufferID > 5 {
+		return 0, false
+	}
+	return bufferID, true
+}
+
+// closestKey returns the known key most similar to an unknown one, if any is close enough.
+func closestKey(key string) string {
+	best, bestDistance := "", 3
+	for _, s := range settings {
+		if d := editDistance(key, s.key); d < bestDistance {
+			best, bestDistance = s.key, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
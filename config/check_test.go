@@ -0,0 +1,163 @@
+package config
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		line     int
+		key      string
+		severity Severity
+		message  string
+	}{
+		{"unknown key with suggestion", "max_itmes=10", 1, "max_itmes", SeverityError, `unknown key "max_itmes" (did you mean "max_items"?)`},
+		{"unknown key", "completely_unknown=1", 1, "completely_unknown", SeverityError, `unknown key "completely_unknown"`},
+		{"out-of-range int", "mask_passwords=3", 1, "mask_passwords", SeverityError, `mask_passwords: invalid value "3": must be an integer from 0 to 2`},
+		{"negative int", "\nmax_items=-1", 2, "max_items", SeverityError, "must be an integer >= 0"},
+		{"bad regex", "password_ignore_pattern=[a-", 1, "password_ignore_pattern", SeverityError, "invalid regular expression"},
+		{"bad bool", "copy_to_primary=maybe", 1, "copy_to_primary", SeverityError, "must be true or false"},
+		{"bad choice", "frontend=kitty", 1, "frontend", SeverityError, "must be one of rofi, fuzzel"},
+		{"missing equals", "# comment\nlimit 10", 2, "", SeverityError, `expected key=value, got "limit 10"`},
+		{"key not allowed in buffer", "[buffer.2]\ndb_path=/tmp/x.db", 2, "db_path", SeverityError, "db_path can't be set in [buffer.2]"},
+		{"invalid section", "[buffer.6]\nlimit=5", 1, "", SeverityError, "unknown section [buffer.6]"},
+		{"unclosed section", "[buffer.1\nlimit=5", 1, "", SeverityError, "unknown section [buffer.1"},
+		{"duplicate key", "limit=10\nlimit=20", 2, "limit", SeverityWarning, "limit overrides the value set on line 1"},
+		{"duplicate key in section", "[buffer.1]\nlimit=10\nlimit=20", 3, "limit", SeverityWarning, "overrides the value set on line 2"},
+		{"warning keeps value", "password_mask_char=ab", 1, "password_mask_char", SeverityWarning, `only the first character "a" is used`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diagnostics, err := parse(strings.NewReader(tt.config), "config.conf", defaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(diagnostics) != 1 {
+				t.Fatalf("parse(%q) diagnostics = %v, want one", tt.config, diagnostics)
+			}
+			d := diagnostics[0]
+			if d.Line != tt.line || d.Key != tt.key || d.Severity != tt.severity || !strings.Contains(d.Message, tt.message) {
+				t.Errorf("parse(%q) = %+v, want line %d, key %q, %s containing %q", tt.config, d, tt.line, tt.key, tt.severity, tt.message)
+			}
+		})
+	}
+}
+
+func TestParseInvalidValueKeepsPrevious(t *testing.T) {
+	cfg := defaultConfig()
+	if _, _, err := parse(strings.NewReader("max_items=20\nmax_items=lots\npassword_mask_char=ab"), "config.conf", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxItems != 20 {
+		t.Errorf("MaxItems after an invalid value = %d, want 20", cfg.MaxItems)
+	}
+	if cfg.PasswordMaskChar != "a" {
+		t.Errorf("PasswordMaskChar after a warning = %q, want %q", cfg.PasswordMaskChar, "a")
+	}
+}
+
+func TestParseInvalidSectionSkipsKeys(t *testing.T) {
+	cfg := defaultConfig()
+	config := "limit=10\n[buffer.9]\nlimit=99\nbogus_key=1\n[buffer.1]\nlimit=5\n"
+	_, diagnostics, err := parse(strings.NewReader(config), "config.conf", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys of the invalid section are neither applied nor reported
+	if len(diagnostics) != 1 || diagnostics[0].Line != 2 {
+		t.Errorf("diagnostics = %v, want only the invalid section", diagnostics)
+	}
+	if cfg.Limit != 10 {
+		t.Errorf("global Limit = %d, want 10", cfg.Limit)
+	}
+	if got := cfg.ForBuffer(1).Limit; got != 5 {
+		t.Errorf("Limit of buffer 1 = %d, want 5", got)
+	}
+}
+
+func TestClosestKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"max_itmes", "max_items"},
+		{"limt", "limit"},
+		{"pinned_markr", "pinned_marker"},
+		{"frontnd", "frontend"},
+		{"xyz", ""},
+		{"color", ""},
+	}
+	for _, tt := range tests {
+		if got := closestKey(tt.key); got != tt.want {
+			t.Errorf("closestKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"limit", "limit", 0},
+		{"limit", "limt", 1},
+		{"kitten", "sitting", 3},
+		{"max_itmes", "max_items", 2},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	report, err := Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Exists || len(report.Diagnostics) != 0 {
+		t.Errorf("Check() without a config file = %+v, want no file and no diagnostics", report)
+	}
+
+	config := "limit=10\nmax_itmes=5\n[buffer.2]\nlimit=3\n"
+	if err := os.MkdirAll(filepath.Join(dir, "clipbox"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "clipbox", "config.conf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Exists || !report.HasErrors() || len(report.Diagnostics) != 1 {
+		t.Errorf("Check() = %+v, want the unknown key as the only error", report)
+	}
+	want := []Setting{
+		{Key: "limit", Buffer: 0, Line: 1, Value: "10", Default: "500"},
+		{Key: "limit", Buffer: 2, Line: 4, Value: "3", Default: "10"},
+	}
+	if len(report.Settings) != len(want) {
+		t.Fatalf("Check() settings = %+v, want %+v", report.Settings, want)
+	}
+	for i := range want {
+		if report.Settings[i] != want[i] {
+			t.Errorf("Check() setting %d = %+v, want %+v", i, report.Settings[i], want[i])
+		}
+	}
+}
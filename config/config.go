@@ -4,32 +4,93 @@ package config
 // SPDX-License-Identifier: GPL-3.0-or-later
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
+	"time"
 )
 
 const configFile = "config.conf"
 
+// TypeKeys are the content types of type_badge, type_badge_color and type_icon.
+// secret stands for credentials and passwords masked by mask_passwords, text for entries of no other type.
+var TypeKeys = []string{"url", "email", "ip", "uuid", "datetime", "path", "file", "json", "yaml", "xml", "html", "sql", "code", "color", "secret", "text"}
+
 type Config struct {
 	Limit                  int
 	PinnedMarker           string
 	UnpinnedMarker         string
-	BufferNames            [5]string // Names for buffers 1-5
-	SeparatorLength        int       // Length of separator between regular and pinned entries
-	MaxDedupeSearch        int       // Maximum number of recent entries to check for duplicates
-	MaxItems               int       // Maximum number of items to store (0 = unlimited)
-	MinStoreLength         int       // Minimum number of characters to store
-	DBPath                 string    // Path to database (empty = use default)
-	PreviewWidth           int       // Maximum number of characters to preview
-	ShowImageIcons         bool      // Show image icons in rofi (default: true)
-	MaskPasswords          int       // Password masking mode: 0 = no masking, 1 = partial, 2 = full (default: 0)
-	PasswordMaskColor      string    // Color for masked password characters (default: "red")
-	PasswordMaskChar       string    // Character used for masking passwords (default: "*")
-	PasswordIgnorePatterns []string  // Regex patterns to exclude from password detection
+	BufferNames            [5]string         // Names for buffers 1-5
+	SeparatorLength        int               // Length of separator between regular and pinned entries
+	MaxDedupeSearch        int               // Maximum number of recent entries to check for duplicates
+	MaxItems               int               // Maximum number of items to store (0 = unlimited)
+	MinStoreLength         int               // Minimum number of characters to store
+	NormalizeLineEndings   bool              // Store text with CRLF line endings converted to LF
+	DBPath                 string            // Path to database (empty = use default)
+	PreviewWidth           int               // Maximum number of characters to preview
+	ShowImageIcons         bool              // Show image icons in rofi (default: true)
+	ShowTypeBadges         bool              // Show a badge with the content type before text entries (default: false)
+	TypeBadges             map[string]string // Badge text by content type, see TypeKeys
+	TypeBadgeColors        map[string]string // Badge color by content type
+	ShowTypeIcons          bool              // Show a theme icon for the content type of text entries (default: false)
+	TypeIcons              map[string]string // Freedesktop icon name by content type
+	MaskPasswords          int               // Password masking mode: 0 = no masking, 1 = partial, 2 = full (default: 0)
+	PasswordMaskColor      string            // Color for masked password characters (default: "red")
+	PasswordMaskChar       string            // Character used for masking passwords (default: "*")
+	PasswordIgnorePatterns []string          // Regex patterns to exclude from password detection
+	SecretThreshold        float64           // Minimum confidence from 0 to 1 for masking secrets (default: 0.5)
+	IgnoreCredentials      bool              // Don't store private keys and credential blocks (default: false)
+	ClipboardMarker        string            // Marker for entries copied to the clipboard
+	PrimaryMarker          string            // Marker for entries from the primary selection
+	PrimaryMaxItems        int               // Maximum number of primary selection items to store (0 = unlimited)
+	PrimaryMinStoreLength  int               // Minimum number of characters to store from the primary selection
+	CopyToPrimary          bool              // Also write selected entries to the primary selection
+	SourceAppCommand       string            // Shell command printing the focused application id
+	ShowSourceApp          bool              // Show the source application in the preview
+	IgnoreApps             []string          // Glob patterns of application ids whose copies are not stored
+	PreStoreHook           string            // Shell command that can rewrite or veto content before it is stored
+	PreStoreHookOnFailure  string            // drop = don't store content when the pre-store hook fails, store = store it unchanged
+	PostStoreHook          string            // Shell command that receives JSON metadata of stored entries
+	HookTimeout            int               // Timeout for the pre-store hook in milliseconds
+	TransformStore         bool              // Store transform results as new entries
+	TimeZones              []string          // Extra time zones of the date transforms
+	MergeSeparator         string            // Separator between merged entries
+	AutoPaste              bool              // Paste the selected entry into the focused window
+	PasteBackend           string            // Typing backend: wtype, ydotool or custom
+	PasteMode              string            // key = send paste_key after copying, type = type the text
+	PasteCommand           string            // Shell command for the custom backend
+	PasteKey               string            // Key chord that pastes in the focused window
+	PasteDelay             int               // Delay before pasting in milliseconds
+	ExpandTemplates        bool              // Expand placeholders such as {{date}} when an entry is selected
+	TemplateMarker         string            // Marker for entries with placeholders
+	Frontend               string            // Launcher used by --list and --pick: rofi, fuzzel, wofi, bemenu, dmenu or fzf
+	FrontendCommand        string            // Shell command replacing the launcher command line used by --pick
+	MaxAge                 time.Duration     // Unpinned entries older than this are removed (0 = never)
+
+	// Values of [buffer.N] sections, applied by ForBuffer
+	bufferOverrides [5][]override
+}
+
+// override is a key set in a [buffer.N] section
+type override struct {
+	key   string
+	value string
+}
+
+// ForBuffer returns the effective config for a buffer, with the [buffer.N] section applied.
+// Returns c itself if the buffer has no section.
+func (c *Config) ForBuffer(bufferID int) *Config {
+	if bufferID < 1 || bufferID > 5 || len(c.bufferOverrides[bufferID-1]) == 0 {
+		return c
+	}
+	bufferConfig := *c
+	for _, o := range c.bufferOverrides[bufferID-1] {
+		// Values were validated when the config was loaded
+		if s, ok := findSetting(o.key); ok {
+			_ = s.parse(&bufferConfig, o.value)
+		}
+	}
+	return &bufferConfig
 }
 
 // GetConfigPath returns the path to the config file.
@@ -47,26 +108,79 @@ func GetConfigPath() (string, error) {
 	return configPath, nil
 }
 
-// LoadConfig reads the configuration file and returns Config with values.
-// Returns default values if config file doesn't exist or on error.
-func LoadConfig() (*Config, error) {
-	config := &Config{
-		Limit:                  500,
-		PinnedMarker:           "",
-		UnpinnedMarker:         "",
-		BufferNames:            [5]string{"", "", "", "", ""},
-		SeparatorLength:        66,
-		MaxDedupeSearch:        100,
-		MaxItems:               500,
-		MinStoreLength:         0,
-		DBPath:                 "",
-		PreviewWidth:           65,
-		ShowImageIcons:         false,
+// defaultConfig returns the configuration used for keys missing in the config file
+func defaultConfig() *Config {
+	return &Config{
+		Limit:                500,
+		PinnedMarker:         "",
+		UnpinnedMarker:       "",
+		BufferNames:          [5]string{"", "", "", "", ""},
+		SeparatorLength:      66,
+		MaxDedupeSearch:      100,
+		MaxItems:             500,
+		MinStoreLength:       0,
+		NormalizeLineEndings: false,
+		DBPath:               "",
+		PreviewWidth:         65,
+		ShowImageIcons:       false,
+		ShowTypeBadges:       false,
+		TypeBadges: map[string]string{
+			"url": "URL", "email": "MAIL", "ip": "IP", "uuid": "UUID", "datetime": "DATE",
+			"path": "PATH", "file": "FILE", "json": "JSON", "yaml": "YAML", "xml": "XML", "html": "HTML",
+			"sql": "SQL", "code": "CODE", "color": "COLOR", "secret": "SECRET",
+		},
+		TypeBadgeColors: map[string]string{
+			"url": "#2563EB", "email": "#7C3AED", "ip": "#0891B2", "uuid": "#64748B", "datetime": "#D97706",
+			"path": "#16A34A", "file": "#0D9488", "json": "#CA8A04", "yaml": "#0284C7", "xml": "#4F46E5",
+			"html": "#E11D48", "sql": "#059669", "code": "#DB2777", "color": "#EA580C", "secret": "#DC2626",
+		},
+		ShowTypeIcons: false,
+		TypeIcons: map[string]string{
+			"url": "text-html", "email": "internet-mail", "ip": "network-wired", "uuid": "text-x-generic",
+			"datetime": "x-office-calendar", "path": "folder", "file": "text-x-generic", "json": "application-json",
+			"yaml": "application-x-yaml", "xml": "text-xml", "html": "text-html", "sql": "application-sql",
+			"code": "text-x-script", "color": "applications-graphics", "secret": "dialog-password", "text": "text-x-generic",
+		},
 		MaskPasswords:          0,
 		PasswordMaskColor:      "#DC2626",
 		PasswordMaskChar:       "*",
 		PasswordIgnorePatterns: []string{},
+		SecretThreshold:        0.5,
+		IgnoreCredentials:      false,
+		ClipboardMarker:        "",
+		PrimaryMarker:          "",
+		PrimaryMaxItems:        50,
+		PrimaryMinStoreLength:  0,
+		CopyToPrimary:          false,
+		SourceAppCommand:       "",
+		ShowSourceApp:          false,
+		IgnoreApps:             []string{},
+		PreStoreHook:           "",
+		PreStoreHookOnFailure:  "drop",
+		PostStoreHook:          "",
+		HookTimeout:            1000,
+		TransformStore:         false,
+		TimeZones:              []string{},
+		MergeSeparator:         "\n",
+		AutoPaste:              false,
+		PasteBackend:           "wtype",
+		PasteMode:              "key",
+		PasteCommand:           "",
+		PasteKey:               "ctrl+v",
+		PasteDelay:             200,
+		ExpandTemplates:        false,
+		TemplateMarker:         "",
+		Frontend:               "rofi",
+		FrontendCommand:        "",
+		MaxAge:                 0,
 	}
+}
+
+// LoadConfig reads the configuration file and returns Config with values.
+// Returns default values if config file doesn't exist or on error.
+// Invalid lines are skipped, 'clipbox check-config' reports them.
+func LoadConfig() (*Config, error) {
+	config := defaultConfig()
 
 	configPath, err := GetConfigPath()
 	if err != nil {
@@ -90,96 +204,8 @@ func LoadConfig() (*Config, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		switch key {
-		case "limit":
-			if limit, err := strconv.Atoi(value); err == nil && limit > 0 {
-				config.Limit = limit
-			}
-		case "pinned_marker":
-			config.PinnedMarker = value
-		case "unpinned_marker":
-			config.UnpinnedMarker = value
-		case "buffer_1_name":
-			config.BufferNames[0] = value
-		case "buffer_2_name":
-			config.BufferNames[1] = value
-		case "buffer_3_name":
-			config.BufferNames[2] = value
-		case "buffer_4_name":
-			config.BufferNames[3] = value
-		case "buffer_5_name":
-			config.BufferNames[4] = value
-		case "separator_length":
-			if length, err := strconv.Atoi(value); err == nil && length > 0 {
-				config.SeparatorLength = length
-			}
-		case "max_dedupe_search":
-			if maxSearch, err := strconv.Atoi(value); err == nil && maxSearch > 0 {
-				config.MaxDedupeSearch = maxSearch
-			}
-		case "max_items":
-			if maxItems, err := strconv.Atoi(value); err == nil && maxItems >= 0 {
-				config.MaxItems = maxItems
-			}
-		case "min_store_length":
-			if minLength, err := strconv.Atoi(value); err == nil && minLength >= 0 {
-				config.MinStoreLength = minLength
-			}
-		case "db_path":
-			config.DBPath = os.ExpandEnv(value)
-		case "preview_width":
-			if width, err := strconv.Atoi(value); err == nil && width > 0 {
-				config.PreviewWidth = width
-			}
-		case "show_image_icons":
-			switch value {
-			case "false", "0", "no":
-				config.ShowImageIcons = false
-			case "true", "1", "yes":
-				config.ShowImageIcons = true
-			}
-		case "mask_passwords":
-			if mode, err := strconv.Atoi(value); err == nil {
-				if mode >= 0 && mode <= 2 {
-					config.MaskPasswords = mode
-				}
-			}
-		case "password_mask_color":
-			if value != "" {
-				config.PasswordMaskColor = value
-			}
-		case "password_mask_char":
-			if value != "" {
-				// Use first rune if multiple characters provided
-				runes := []rune(value)
-				if len(runes) > 0 {
-					config.PasswordMaskChar = string(runes[0])
-				}
-			}
-		case "password_ignore_pattern":
-			if value != "" {
-				config.PasswordIgnorePatterns = append(config.PasswordIgnorePatterns, value)
-			}
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return config, fmt.Errorf("error reading config: %w", err)
+	if _, _, err := parse(file, configPath, config); err != nil {
+		return config, err
 	}
 
 	return config, nil
@@ -0,0 +1,88 @@
+package config
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForBuffer(t *testing.T) {
+	config := `limit=100
+max_items=1000
+pinned_marker=*
+[buffer.2]
+limit=20
+max_age=7d
+pinned_marker=
+[buffer.3]
+max_items=10
+max_items=30
+[buffer.4]
+limit=many
+`
+	cfg := defaultConfig()
+	if _, _, err := parse(strings.NewReader(config), "config.conf", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		buffer       int
+		limit        int
+		maxItems     int
+		maxAge       time.Duration
+		pinnedMarker string
+	}{
+		{1, 100, 1000, 0, "*"},
+		{2, 20, 1000, 7 * 24 * time.Hour, ""},
+		{3, 100, 30, 0, "*"},
+		{4, 100, 1000, 0, "*"},
+		{0, 100, 1000, 0, "*"},
+		{6, 100, 1000, 0, "*"},
+	}
+	for _, tt := range tests {
+		got := cfg.ForBuffer(tt.buffer)
+		if got.Limit != tt.limit || got.MaxItems != tt.maxItems || got.MaxAge != tt.maxAge || got.PinnedMarker != tt.pinnedMarker {
+			t.Errorf("ForBuffer(%d) = limit %d, max_items %d, max_age %s, pinned_marker %q, want %d, %d, %s, %q",
+				tt.buffer, got.Limit, got.MaxItems, got.MaxAge, got.PinnedMarker, tt.limit, tt.maxItems, tt.maxAge, tt.pinnedMarker)
+		}
+	}
+
+	if cfg.ForBuffer(1) != cfg {
+		t.Error("ForBuffer of a buffer without a section returned a copy, want the config itself")
+	}
+	if cfg.Limit != 100 || cfg.MaxAge != 0 {
+		t.Errorf("global config changed by ForBuffer: limit %d, max_age %s", cfg.Limit, cfg.MaxAge)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"30m", 30 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"-1d", 0, true},
+		{"-5m", 0, true},
+		{"week", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAge(%q) = %s, %v, want %s, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil {
+			if back, _ := parseAge(formatAge(got)); back != got {
+				t.Errorf("parseAge(formatAge(%s)) = %s", got, back)
+			}
+		}
+	}
+}
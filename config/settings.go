@@ -0,0 +1,350 @@
+package config
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describes a config key: how its value is parsed and how the effective value is shown.
+type setting struct {
+	key        string
+	repeatable bool // The key may appear several times, each value is appended
+	perBuffer  bool // The key may be set in [buffer.N] sections
+	parse      func(c *Config, value string) error
+	value      func(c *Config) string
+}
+
+// settings lists all config keys in the order of config.conf
+var settings = []setting{
+	perBuffer(intSetting("limit", 1, math.MaxInt, func(c *Config) *int { return &c.Limit })),
+	perBuffer(stringSetting("pinned_marker", func(c *Config) *string { return &c.PinnedMarker })),
+	perBuffer(stringSetting("unpinned_marker", func(c *Config) *string { return &c.UnpinnedMarker })),
+	stringSetting("buffer_1_name", func(c *Config) *string { return &c.BufferNames[0] }),
+	stringSetting("buffer_2_name", func(c *Config) *string { return &c.BufferNames[1] }),
+	stringSetting("buffer_3_name", func(c *Config) *string { return &c.BufferNames[2] }),
+	stringSetting("buffer_4_name", func(c *Config) *string { return &c.BufferNames[3] }),
+	stringSetting("buffer_5_name", func(c *Config) *string { return &c.BufferNames[4] }),
+	intSetting("separator_length", 1, math.MaxInt, func(c *Config) *int { return &c.SeparatorLength }),
+	intSetting("max_dedupe_search", 1, math.MaxInt, func(c *Config) *int { return &c.MaxDedupeSearch }),
+	perBuffer(intSetting("max_items", 0, math.MaxInt, func(c *Config) *int { return &c.MaxItems })),
+	perBuffer(intSetting("min_store_length", 0, math.MaxInt, func(c *Config) *int { return &c.MinStoreLength })),
+	perBuffer(boolSetting("normalize_line_endings", func(c *Config) *bool { return &c.NormalizeLineEndings })),
+	perBuffer(setting{
+		key: "max_age",
+		parse: func(c *Config, value string) error {
+			age, err := parseAge(value)
+			if err != nil {
+				return err
+			}
+			c.MaxAge = age
+			return nil
+		},
+		value: func(c *Config) string { return formatAge(c.MaxAge) },
+	}),
+	{
+		key: "db_path",
+		parse: func(c *Config, value string) error {
+			c.DBPath = os.ExpandEnv(value)
+			return nil
+		},
+		value: func(c *Config) string { return strconv.Quote(c.DBPath) },
+	},
+	perBuffer(intSetting("preview_width", 1, math.MaxInt, func(c *Config) *int { return &c.PreviewWidth })),
+	boolSetting("show_image_icons", func(c *Config) *bool { return &c.ShowImageIcons }),
+	boolSetting("show_type_badges", func(c *Config) *bool { return &c.ShowTypeBadges }),
+	mapSetting("type_badge", TypeKeys, func(c *Config) *map[string]string { return &c.TypeBadges }),
+	mapSetting("type_badge_color", TypeKeys, func(c *Config) *map[string]string { return &c.TypeBadgeColors }),
+	boolSetting("show_type_icons", func(c *Config) *bool { return &c.ShowTypeIcons }),
+	mapSetting("type_icon", TypeKeys, func(c *Config) *map[string]string { return &c.TypeIcons }),
+	perBuffer(intSetting("mask_passwords", 0, 2, func(c *Config) *int { return &c.MaskPasswords })),
+	perBuffer(floatSetting("secret_threshold", 0, 1, func(c *Config) *float64 { return &c.SecretThreshold })),
+	nonEmptySetting("password_mask_color", func(c *Config) *string { return &c.PasswordMaskColor }),
+	{
+		key: "password_mask_char",
+		parse: func(c *Config, value string) error {
+			runes := []rune(value)
+			if len(runes) == 0 {
+				return fmt.Errorf("must not be empty")
+			}
+			// Use first rune if multiple characters provided
+			c.PasswordMaskChar = string(runes[0])
+			if len(runes) > 1 {
+				return warningf("only the first character %q is used", c.PasswordMaskChar)
+			}
+			return nil
+		},
+		value: func(c *Config) string { return strconv.Quote(c.PasswordMaskChar) },
+	},
+	listSetting("password_ignore_pattern", func(value string) error {
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return nil
+	}, func(c *Config) *[]string { return &c.PasswordIgnorePatterns }),
+	boolSetting("ignore_credentials", func(c *Config) *bool { return &c.IgnoreCredentials }),
+	perBuffer(stringSetting("clipboard_marker", func(c *Config) *string { return &c.ClipboardMarker })),
+	perBuffer(stringSetting("primary_marker", func(c *Config) *string { return &c.PrimaryMarker })),
+	intSetting("primary_max_items", 0, math.MaxInt, func(c *Config) *int { return &c.PrimaryMaxItems }),
+	intSetting("primary_min_store_length", 0, math.MaxInt, func(c *Config) *int { return &c.PrimaryMinStoreLength }),
+	boolSetting("copy_to_primary", func(c *Config) *bool { return &c.CopyToPrimary }),
+	stringSetting("source_app_command", func(c *Config) *string { return &c.SourceAppCommand }),
+	boolSetting("show_source_app", func(c *Config) *bool { return &c.ShowSourceApp }),
+	listSetting("ignore_app", func(value string) error {
+		if _, err := path.Match(value, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern: %v", err)
+		}
+		return nil
+	}, func(c *Config) *[]string { return &c.IgnoreApps }),
+	stringSetting("pre_store_hook", func(c *Config) *string { return &c.PreStoreHook }),
+	choiceSetting("pre_store_hook_on_failure", []string{"drop", "store"}, func(c *Config) *string { return &c.PreStoreHookOnFailure }),
+	stringSetting("post_store_hook", func(c *Config) *string { return &c.PostStoreHook }),
+	intSetting("hook_timeout", 1, math.MaxInt, func(c *Config) *int { return &c.HookTimeout }),
+	boolSetting("transform_store", func(c *Config) *bool { return &c.TransformStore }),
+	listSetting("time_zone", func(value string) error {
+		if _, err := time.LoadLocation(value); err != nil {
+			return fmt.Errorf("unknown time zone")
+		}
+		return nil
+	}, func(c *Config) *[]string { return &c.TimeZones }),
+	{
+		key: "merge_separator",
+		parse: func(c *Config, value string) error {
+			// Allow escape sequences such as \n and \t
+			if unquoted, err := strconv.Unquote(`"` + value + `"`); err == nil {
+				c.MergeSeparator = unquoted
+			} else {
+				c.MergeSeparator = value
+			}
+			return nil
+		},
+		value: func(c *Config) string { return strconv.Quote(c.MergeSeparator) },
+	},
+	boolSetting("auto_paste", func(c *Config) *bool { return &c.AutoPaste }),
+	choiceSetting("paste_backend", []string{"wtype", "ydotool", "custom"}, func(c *Config) *string { return &c.PasteBackend }),
+	choiceSetting("paste_mode", []string{"key", "type"}, func(c *Config) *string { return &c.PasteMode }),
+	stringSetting("paste_command", func(c *Config) *string { return &c.PasteCommand }),
+	nonEmptySetting("paste_key", func(c *Config) *string { return &c.PasteKey }),
+	intSetting("paste_delay", 0, math.MaxInt, func(c *Config) *int { return &c.PasteDelay }),
+	boolSetting("expand_templates", func(c *Config) *bool { return &c.ExpandTemplates }),
+	perBuffer(stringSetting("template_marker", func(c *Config) *string { return &c.TemplateMarker })),
+	choiceSetting("frontend", []string{"rofi", "fuzzel", "wofi", "bemenu", "dmenu", "fzf"}, func(c *Config) *string { return &c.Frontend }),
+	stringSetting("frontend_command", func(c *Config) *string { return &c.FrontendCommand }),
+}
+
+// findSetting returns the setting for a config key.
+func findSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// perBuffer allows a setting in [buffer.N] sections
+func perBuffer(s setting) setting {
+	s.perBuffer = true
+	return s
+}
+
+// parseAge parses a duration such as 1d, 12h or 30m, 0 disables expiry
+func parseAge(value string) (time.Duration, error) {
+	if value == "0" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("must be a duration such as 7d, 12h or 30m")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("must be a duration such as 7d, 12h or 30m")
+	}
+	return age, nil
+}
+
+// formatAge formats a duration as accepted by parseAge
+func formatAge(age time.Duration) string {
+	if age == 0 {
+		return "0"
+	}
+	if age%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", age/(24*time.Hour))
+	}
+	return age.String()
+}
+
+// warning is a parse error that still applies the value
+type warning struct{ msg string }
+
+func (w warning) Error() string { return w.msg }
+
+func warningf(format string, a ...any) error {
+	return warning{fmt.Sprintf(format, a...)}
+}
+
+func intSetting(key string, min, max int, field func(c *Config) *int) setting {
+	return setting{
+		key: key,
+		parse: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < min || n > max {
+				switch {
+				case max == math.MaxInt:
+					return fmt.Errorf("must be an integer >= %d", min)
+				default:
+					return fmt.Errorf("must be an integer from %d to %d", min, max)
+				}
+			}
+			*field(c) = n
+			return nil
+		},
+		value: func(c *Config) string { return strconv.Itoa(*field(c)) },
+	}
+}
+
+func floatSetting(key string, min, max float64, field func(c *Config) *float64) setting {
+	return setting{
+		key: key,
+		parse: func(c *Config, value string) error {
+			x, err := strconv.ParseFloat(value, 64)
+			if err != nil || x < min || x > max {
+				return fmt.Errorf("must be a number from %g to %g", min, max)
+			}
+			*field(c) = x
+			return nil
+		},
+		value: func(c *Config) string { return strconv.FormatFloat(*field(c), 'g', -1, 64) },
+	}
+}
+
+func boolSetting(key string, field func(c *Config) *bool) setting {
+	return setting{
+		key: key,
+		parse: func(c *Config, value string) error {
+			switch value {
+			case "false", "0", "no":
+				*field(c) = false
+			case "true", "1", "yes":
+				*field(c) = true
+			default:
+				return fmt.Errorf("must be true or false")
+			}
+			return nil
+		},
+		value: func(c *Config) string { return strconv.FormatBool(*field(c)) },
+	}
+}
+
+func stringSetting(key string, field func(c *Config) *string) setting {
+	return setting{
+		key: key,
+		parse: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+		value: func(c *Config) string { return strconv.Quote(*field(c)) },
+	}
+}
+
+// nonEmptySetting is a string setting that keeps its default when set to an empty value
+func nonEmptySetting(key string, field func(c *Config) *string) setting {
+	s := stringSetting(key, field)
+	s.parse = func(c *Config, value string) error {
+		if value == "" {
+			return fmt.Errorf("must not be empty")
+		}
+		*field(c) = value
+		return nil
+	}
+	return s
+}
+
+func choiceSetting(key string, choices []string, field func(c *Config) *string) setting {
+	s := stringSetting(key, field)
+	s.parse = func(c *Config, value string) error {
+		for _, choice := range choices {
+			if value == choice {
+				*field(c) = value
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(choices, ", "))
+	}
+	return s
+}
+
+// mapSetting is a repeatable key with "name:value" values, where name is one of names.
+// Each value replaces the previous one of the same name, an empty value clears it.
+func mapSetting(key string, names []string, field func(c *Config) *map[string]string) setting {
+	return setting{
+		key:        key,
+		repeatable: true,
+		parse: func(c *Config, value string) error {
+			name, v, ok := strings.Cut(value, ":")
+			if !ok {
+				return fmt.Errorf("must be NAME:VALUE")
+			}
+			name = strings.TrimSpace(name)
+			if !slices.Contains(names, name) {
+				return fmt.Errorf("unknown name %q, must be one of %s", name, strings.Join(names, ", "))
+			}
+			// Copy the map, it may be shared with the defaults
+			m := maps.Clone(*field(c))
+			if m == nil {
+				m = map[string]string{}
+			}
+			m[name] = strings.TrimSpace(v)
+			*field(c) = m
+			return nil
+		},
+		value: func(c *Config) string {
+			var pairs []string
+			for _, name := range names {
+				if v, ok := (*field(c))[name]; ok && v != "" {
+					pairs = append(pairs, name+":"+strconv.Quote(v))
+				}
+			}
+			return "[" + strings.Join(pairs, ", ") + "]"
+		},
+	}
+}
+
+// listSetting is a repeatable key whose values are validated and appended
+func listSetting(key string, validate func(value string) error, field func(c *Config) *[]string) setting {
+	return setting{
+		key:        key,
+		repeatable: true,
+		parse: func(c *Config, value string) error {
+			if value == "" {
+				return nil
+			}
+			if err := validate(value); err != nil {
+				return err
+			}
+			*field(c) = append(*field(c), value)
+			return nil
+		},
+		value: func(c *Config) string {
+			quoted := make([]string, len(*field(c)))
+			for i, v := range *field(c) {
+				quoted[i] = strconv.Quote(v)
+			}
+			return "[" + strings.Join(quoted, ", ") + "]"
+		},
+	}
+}
@@ -29,7 +29,15 @@ func InitDB(db *sql.DB) error {
         is_pinned INTEGER DEFAULT 0,
         preview TEXT NOT NULL DEFAULT '',
         content BLOB NOT NULL,
-        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        source TEXT NOT NULL DEFAULT 'clipboard',
+        source_app TEXT NOT NULL DEFAULT '',
+        template_disabled INTEGER NOT NULL DEFAULT 0,
+        mime_type TEXT NOT NULL DEFAULT ''
+    );
+    CREATE TABLE IF NOT EXISTS meta (
+        key TEXT PRIMARY KEY,
+        value TEXT NOT NULL
     );
     CREATE TABLE IF NOT EXISTS current_buffer (
         id INTEGER PRIMARY KEY CHECK(id = 1),
@@ -47,6 +55,55 @@ func InitDB(db *sql.DB) error {
 		return fmt.Errorf("failed to create table: %w", err)
 	}
 
+	// Columns added after the first release, missing in older databases
+	if err := ensureColumn(db, "clipboard", "source", "TEXT NOT NULL DEFAULT 'clipboard'"); err != nil {
+		return err
+	}
+	if err := ensureColumn(db, "clipboard", "source_app", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := ensureColumn(db, "clipboard", "template_disabled", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := ensureColumn(db, "clipboard", "mime_type", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	// Filled in by RebuildPreviews for entries stored by older versions
+	if err := ensureColumn(db, "clipboard", "content_type", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureColumn adds a column to an existing table if it is missing
+func ensureColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to get table info: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan table info: %w", err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating table info: %w", err)
+	}
+	rows.Close()
+
+	alterSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := db.Exec(alterSQL); err != nil {
+		return fmt.Errorf("failed to add column %s: %w", column, err)
+	}
 	return nil
 }
 
@@ -0,0 +1,77 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"clipbox/utils"
+)
+
+// postStoreEvent is the JSON metadata passed to the post-store hook on stdin
+type postStoreEvent struct {
+	ID        int    `json:"id"`
+	Buffer    int    `json:"buffer"`
+	Type      string `json:"type"`
+	Size      int    `json:"size"`
+	Source    string `json:"source"`
+	SourceApp string `json:"source_app"`
+}
+
+// vetoExitCode is the exit code of the pre-store hook that vetoes storing
+const vetoExitCode = 1
+
+// runPreStoreHook passes content to the pre-store hook on stdin.
+// Exit code 0 with output replaces the content, 0 without output keeps it,
+// and vetoExitCode vetoes storing (ok is false).
+// Other exit codes (such as 126 and 127 of sh), signals, timeouts and start errors
+// are hook failures: content is stored unchanged if onFailure is "store" and dropped otherwise.
+func runPreStoreHook(hook string, content []byte, timeout time.Duration, onFailure string) (result []byte, ok bool) {
+	if hook == "" {
+		return content, true
+	}
+
+	output, err := utils.RunCommand(hook, content, timeout)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == vetoExitCode {
+			return nil, false
+		}
+		if onFailure == "store" {
+			fmt.Fprintf(os.Stderr, "Warning: pre_store_hook failed, storing unchanged: %v\n", err)
+			return content, true
+		}
+		fmt.Fprintf(os.Stderr, "Warning: pre_store_hook failed, content not stored: %v\n", err)
+		return nil, false
+	}
+
+	if len(output) == 0 {
+		return content, true
+	}
+	return output, true
+}
+
+// runPostStoreHook starts the post-store hook in the background with metadata of a stored entry as JSON,
+// so a slow hook doesn't hold up clipboard capture.
+// Failures to start it are reported as warnings, the entry is already stored.
+func runPostStoreHook(hook string, event postStoreEvent) {
+	if hook == "" {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to encode post_store_hook event: %v\n", err)
+		return
+	}
+
+	if err := utils.StartCommand(hook, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: post_store_hook failed: %v\n", err)
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"clipbox/utils"
+)
+
+func TestRunPreStoreHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		hook      string
+		onFailure string
+		want      string
+		wantOK    bool
+	}{
+		{"no hook", "", "drop", "secret", true},
+		{"replace", "tr a-z A-Z", "drop", "SECRET", true},
+		{"keep", "cat > /dev/null", "drop", "secret", true},
+		{"veto", "exit 1", "drop", "", false},
+		{"veto when failures store", "exit 1", "store", "", false},
+		{"other exit code", "exit 2", "drop", "", false},
+		{"not executable", "exit 126", "drop", "", false},
+		{"missing command", "clipbox-no-such-command", "drop", "", false},
+		{"signal", "kill -TERM $$", "drop", "", false},
+		{"timeout", "sleep 5", "drop", "", false},
+		{"other exit code stored", "exit 2", "store", "secret", true},
+		{"missing command stored", "clipbox-no-such-command", "store", "secret", true},
+		{"timeout stored", "sleep 5", "store", "secret", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := runPreStoreHook(tt.hook, []byte("secret"), 200*time.Millisecond, tt.onFailure)
+			if string(got) != tt.want || ok != tt.wantOK {
+				t.Errorf("runPreStoreHook(%q, %s) = %q, %v, want %q, %v", tt.hook, tt.onFailure, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestStorePreStoreHookFailure(t *testing.T) {
+	testEnv(t, "pre_store_hook=exit 3\n")
+	if err := StoreContent([]byte("secret"), utils.SourceClipboard, 0); err != nil {
+		t.Fatal(err)
+	}
+	listing, err := LoadListing(0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listing.Entries) != 0 {
+		t.Errorf("failing pre-store hook stored %d entries, want none", len(listing.Entries))
+	}
+}
+
+func TestRunPostStoreHookInBackground(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.json")
+	start := time.Now()
+	runPostStoreHook("sleep 0.5; cat > "+utils.ShellQuote(output), postStoreEvent{ID: 42, Buffer: 1, Type: "text", Size: 6})
+	if elapsed := time.Since(start); elapsed > 300*time.Millisecond {
+		t.Errorf("runPostStoreHook waited %s for the hook", elapsed)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		data, err := os.ReadFile(output)
+		if err == nil && len(data) > 0 {
+			var event postStoreEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				t.Fatalf("hook received %q: %v", data, err)
+			}
+			if event.ID != 42 || event.Size != 6 {
+				t.Errorf("hook received %+v, want id 42 and size 6", event)
+			}
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("post-store hook did not write the event")
+}
@@ -4,39 +4,74 @@ package database
 // SPDX-License-Identifier: GPL-3.0-or-later
 
 import (
+	"database/sql"
 	"fmt"
-	"strings"
+	"os"
 
 	"clipbox/config"
+	"clipbox/detect"
+	"clipbox/preview"
+	"clipbox/utils"
 )
 
-// List outputs clipboard entries in rofi script mode format.
-// Entries are sorted by ID (newest first), with pinned entries repeated at the end.
-func List(limit int) error {
+// Entry is a single clipboard entry as shown in the list, independent of the launcher.
+type Entry struct {
+	ID     int    `json:"id"`
+	Pinned bool   `json:"pinned"`
+	Source string `json:"source"`
+	Markup string `json:"markup"` // Pango markup of the preview, including markers
+	Text   string `json:"text"`   // Preview as plain text
+	Icon   string `json:"icon,omitempty"`
+}
+
+// Listing holds the entries of the current buffer.
+// Pinned entries are additionally collected in Pinned for the section at the end.
+type Listing struct {
+	Buffer     int     `json:"buffer"`
+	BufferName string  `json:"buffer_name"`
+	Entries    []Entry `json:"entries"`
+	Pinned     []Entry `json:"pinned"`
+}
+
+// LoadListing reads the entries of the current buffer.
+// A limit of 0 or less uses the limit from config.
+// If app is not empty, only entries copied from that application are returned.
+func LoadListing(limit int, app string) (*Listing, error) {
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	if limit <= 0 {
-		limit = cfg.Limit
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	db, err := OpenDB()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer db.Close()
 
+	// Previews generated with other settings are rendered again for the listed entries,
+	// all entries are rebuilt in the background
+	stale, err := PreviewsStale(db, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if stale {
+		requestRebuild(db, cfg)
+	}
+
 	currentBuffer, err := GetCurrentBuffer(db)
 	if err != nil {
-		return fmt.Errorf("failed to get current buffer: %w", err)
+		return nil, fmt.Errorf("failed to get current buffer: %w", err)
+	}
+
+	bufferCfg := cfg.ForBuffer(currentBuffer)
+	if limit <= 0 {
+		limit = bufferCfg.Limit
 	}
 
-	// Rofi script mode configuration
-	fmt.Print("\x00use-hot-keys\x1ftrue\n")
-	fmt.Print("\x00keep-selection\x1ftrue\n")
-	fmt.Print("\x00markup-rows\x1ftrue\n")
+	// Entries past max_age are removed on the next store, until then they are hidden
+	maxAge := ""
+	if bufferCfg.MaxAge > 0 {
+		maxAge = ageModifier(bufferCfg.MaxAge)
+	}
 
 	var bufferName string
 	if currentBuffer >= 1 && currentBuffer <= 5 {
@@ -45,72 +80,127 @@ func List(limit int) error {
 	if bufferName == "" {
 		bufferName = fmt.Sprintf("Buffer %d", currentBuffer)
 	}
-	fmt.Printf("\x00prompt\x1f%s\n", bufferName)
 
-	hasRows := false
-	hasPinnedRows := false
+	listing := &Listing{
+		Buffer:     currentBuffer,
+		BufferName: bufferName,
+		Entries:    []Entry{},
+		Pinned:     []Entry{},
+	}
+
+	// IDs of entries whose previews are generated again after reading the rows
+	refresh := map[int]bool{}
 
 	// Query all entries with limit
 	query := `
-    SELECT preview FROM clipboard
+    SELECT id, is_pinned, preview, source, content_type FROM clipboard
     WHERE buffer_id = ?
+    AND (? = '' OR source_app = ? COLLATE NOCASE)
+    AND (? = '' OR is_pinned = 1 OR created_at >= datetime('now', ?))
     ORDER BY id DESC
     LIMIT ?
     `
 
-	rows, err := db.Query(query, currentBuffer, limit)
+	rows, err := db.Query(query, currentBuffer, app, app, maxAge, maxAge, limit)
 	if err != nil {
-		return fmt.Errorf("failed to query: %w", err)
+		return nil, fmt.Errorf("failed to query: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var preview string
-		if err := rows.Scan(&preview); err != nil {
-			return fmt.Errorf("failed to scan row: %w", err)
+		e, contentType, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
-		hasRows = true
-		fmt.Printf("%s\n", preview)
+		if stale || contentType == detect.TypeFile {
+			refresh[e.ID] = true
+		}
+		listing.Entries = append(listing.Entries, e)
 	}
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating rows: %w", err)
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	if err := refreshPreviews(db, cfg, listing.Entries, refresh); err != nil {
+		return nil, err
 	}
 
 	// Query pinned entries for the "pinned section" at the end
 	queryPinned := `
-    SELECT preview FROM clipboard
+    SELECT id, is_pinned, preview, source, content_type FROM clipboard
     WHERE buffer_id = ? AND is_pinned = 1
+    AND (? = '' OR source_app = ? COLLATE NOCASE)
     ORDER BY id DESC
     LIMIT 1000
     `
 
-	rowsPinned, err := db.Query(queryPinned, currentBuffer)
+	rowsPinned, err := db.Query(queryPinned, currentBuffer, app, app)
 	if err != nil {
-		return fmt.Errorf("failed to query pinned: %w", err)
+		return nil, fmt.Errorf("failed to query pinned: %w", err)
 	}
 	defer rowsPinned.Close()
 
 	for rowsPinned.Next() {
-		var preview string
-		if err := rowsPinned.Scan(&preview); err != nil {
-			return fmt.Errorf("failed to scan pinned row: %w", err)
+		e, contentType, err := scanEntry(rowsPinned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pinned row: %w", err)
 		}
-		if !hasPinnedRows {
-			hasPinnedRows = true
-			if hasRows {
-				separator := strings.Repeat("—", cfg.SeparatorLength)
-				fmt.Printf("%s\x00info\x1f0\n", separator)
-			}
+		if stale || contentType == detect.TypeFile {
+			refresh[e.ID] = true
 		}
-		fmt.Printf("%s\n", preview)
+		listing.Pinned = append(listing.Pinned, e)
 	}
 	if err := rowsPinned.Err(); err != nil {
-		return fmt.Errorf("error iterating pinned rows: %w", err)
+		return nil, fmt.Errorf("error iterating pinned rows: %w", err)
+	}
+	if err := refreshPreviews(db, cfg, listing.Pinned, refresh); err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+// scanEntry reads a row selected as id, is_pinned, preview, source, content_type
+func scanEntry(row interface{ Scan(dest ...any) error }) (Entry, string, error) {
+	var e Entry
+	var line, contentType string
+	if err := row.Scan(&e.ID, &e.Pinned, &line, &e.Source, &contentType); err != nil {
+		return e, "", err
 	}
+	setPreview(&e, line)
+	return e, contentType, nil
+}
 
-	if !hasRows && !hasPinnedRows {
-		fmt.Printf(" (No entries in buffer %d)\x00info\x1f0\n", currentBuffer)
+// setPreview sets the markup, text and icon of an entry from its preview line
+func setPreview(e *Entry, line string) {
+	e.Markup, e.Icon = preview.ParseLine(line, e.ID)
+	e.Text = utils.StripMarkup(e.Markup)
+}
+
+// refreshPreviews generates the previews of the entries in ids again with cfg:
+// all entries while the stored previews are stale, and file entries,
+// as the files may have been changed or deleted since they were stored.
+func refreshPreviews(db *sql.DB, cfg *config.Config, entries []Entry, ids map[int]bool) error {
+	var listed []int
+	for _, e := range entries {
+		if ids[e.ID] {
+			listed = append(listed, e.ID)
+		}
+	}
+	records, err := getRecords(db, listed)
+	if err != nil {
+		return err
 	}
 
+	for i, j := 0, 0; i < len(entries) && j < len(records); i++ {
+		if entries[i].ID != records[j].ID {
+			continue
+		}
+		record := records[j]
+		j++
+		bufferCfg := cfg.ForBuffer(record.Buffer)
+		classification := classify(record.Content, bufferCfg)
+		iconPath, hasIcon := entryIcon(record.ID, record.Content, classification, bufferCfg, false)
+		setPreview(&entries[i], preview.GeneratePreviewClassified(record.PreviewEntry(hasIcon, iconPath), classification, cfg))
+	}
 	return nil
 }
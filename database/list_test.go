@@ -0,0 +1,111 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"clipbox/config"
+	"clipbox/utils"
+)
+
+func TestLoadListingStalePreviews(t *testing.T) {
+	testEnv(t, "")
+	for _, content := range []string{"first entry with a long text", "second entry with a long text"} {
+		if err := StoreContent([]byte(content), utils.SourceClipboard, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configPath := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "clipbox", "config.conf")
+	if err := os.WriteFile(configPath, []byte("preview_width=12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rebuilds := 0
+	StartRebuild = func() error {
+		rebuilds++
+		return nil
+	}
+	t.Cleanup(func() { StartRebuild = nil })
+
+	// The listed entries are rendered with the new settings before the rebuild finished
+	for range 2 {
+		listing, err := LoadListing(1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listing.Entries) != 1 || strings.TrimSpace(listing.Entries[0].Text) != "second entry…" {
+			t.Errorf("LoadListing entries = %+v, want the second entry truncated", listing.Entries)
+		}
+	}
+	if rebuilds != 1 {
+		t.Errorf("LoadListing started %d rebuilds, want 1", rebuilds)
+	}
+
+	// The stored previews are left to the rebuild
+	db, err := OpenDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stale, err := PreviewsStale(db, cfg); err != nil || !stale {
+		t.Errorf("PreviewsStale = %v, %v, want true", stale, err)
+	}
+	if _, err := RebuildPreviews(db, cfg, false); err != nil {
+		t.Fatal(err)
+	}
+	if stale, err := PreviewsStale(db, cfg); err != nil || stale {
+		t.Errorf("PreviewsStale after rebuilding = %v, %v, want false", stale, err)
+	}
+}
+
+func TestRebuildPreviewsBatches(t *testing.T) {
+	testEnv(t, "")
+	for i := range 5 {
+		if err := StoreContent([]byte(strings.Repeat("x", i+1)+" entry"), utils.SourceClipboard, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batchSize := rebuildBatchSize
+	rebuildBatchSize = 2
+	t.Cleanup(func() { rebuildBatchSize = batchSize })
+
+	db, err := OpenDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("UPDATE clipboard SET preview = 'stale', content_type = ''"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := RebuildPreviews(db, cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 5 {
+		t.Errorf("RebuildPreviews updated %d entries, want 5", updated)
+	}
+	var stale int
+	if err := db.QueryRow("SELECT COUNT(*) FROM clipboard WHERE preview = 'stale' OR content_type = ''").Scan(&stale); err != nil {
+		t.Fatal(err)
+	}
+	if stale != 0 {
+		t.Errorf("%d entries kept their stale preview", stale)
+	}
+	if stale, err := PreviewsStale(db, cfg); err != nil || stale {
+		t.Errorf("PreviewsStale after rebuilding = %v, %v, want false", stale, err)
+	}
+}
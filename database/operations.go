@@ -8,7 +8,6 @@ import (
 
 	"clipbox/config"
 	"clipbox/image"
-	"clipbox/preview"
 )
 
 // GetContentByID retrieves the content of a clipboard entry by its ID
@@ -102,6 +101,16 @@ func SwitchToPreviousBuffer() error {
 
 // TogglePin toggles the pinned status of an entry and regenerates its preview
 func TogglePin(id int) error {
+	return updatePinned(id, func(pinned bool) bool { return !pinned })
+}
+
+// SetPinned sets the pinned status of an entry and regenerates its preview
+func SetPinned(id int, pinned bool) error {
+	return updatePinned(id, func(bool) bool { return pinned })
+}
+
+// updatePinned changes the pinned status of an entry to the result of change and regenerates its preview
+func updatePinned(id int, change func(pinned bool) bool) error {
 	if id <= 0 {
 		return fmt.Errorf("invalid id: %d", id)
 	}
@@ -118,35 +127,93 @@ func TogglePin(id int) error {
 	}
 
 	var currentPinned int
-	var content []byte
-	err = db.QueryRow("SELECT is_pinned, content FROM clipboard WHERE id = ?", id).Scan(&currentPinned, &content)
+	err = db.QueryRow("SELECT is_pinned FROM clipboard WHERE id = ?", id).Scan(&currentPinned)
 	if err != nil {
 		return fmt.Errorf("failed to get current pinned status: %w", err)
 	}
 
 	var newPinned int
-	if currentPinned == 1 {
-		newPinned = 0
-	} else {
+	if change(currentPinned == 1) {
 		newPinned = 1
 	}
 
-	var hasIcon bool
-	var iconPath string
-	if cfg.ShowImageIcons {
-		if path, ok := image.GetIconPath(id); ok {
-			hasIcon = true
-			iconPath = path
-		}
+	_, err = db.Exec("UPDATE clipboard SET is_pinned = ? WHERE id = ?", newPinned, id)
+	if err != nil {
+		return fmt.Errorf("failed to update pin: %w", err)
+	}
+
+	return updatePreview(db, id, cfg)
+}
+
+// ToggleTemplate enables or disables placeholder expansion for an entry and regenerates its preview
+func ToggleTemplate(id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid id: %d", id)
 	}
 
-	previewText := preview.GeneratePreview(id, content, newPinned, hasIcon, iconPath, cfg)
-	_, err = db.Exec("UPDATE clipboard SET is_pinned = ?, preview = ? WHERE id = ?", newPinned, previewText, id)
+	db, err := OpenDB()
 	if err != nil {
-		return fmt.Errorf("failed to toggle pin: %w", err)
+		return err
 	}
+	defer db.Close()
 
-	return nil
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	result, err := db.Exec("UPDATE clipboard SET template_disabled = 1 - template_disabled WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to toggle template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("entry with id %d not found", id)
+	}
+
+	return updatePreview(db, id, cfg)
+}
+
+// MoveEntry moves a clipboard entry to another buffer
+func MoveEntry(id int, bufferID int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid id: %d", id)
+	}
+	if bufferID < 1 || bufferID > 5 {
+		return fmt.Errorf("buffer_id must be between 1 and 5, got %d", bufferID)
+	}
+
+	db, err := OpenDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := db.Exec("UPDATE clipboard SET buffer_id = ? WHERE id = ?", bufferID, id)
+	if err != nil {
+		return fmt.Errorf("failed to move entry: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("entry with id %d not found", id)
+	}
+
+	// Markers and preview settings may differ between buffers
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return updatePreview(db, id, cfg)
 }
 
 // DeleteEntry deletes a clipboard entry by its ID and removes associated icon file
@@ -0,0 +1,168 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"clipbox/config"
+	"clipbox/preview"
+)
+
+// previewFingerprintKey is the meta key of the fingerprint the stored previews were generated with
+const previewFingerprintKey = "preview_fingerprint"
+
+// getMeta returns a value of the meta table, or "" if the key is missing
+func getMeta(db *sql.DB, key string) (string, error) {
+	var value string
+	err := db.QueryRow("SELECT value FROM meta WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// setMeta stores a value in the meta table
+func setMeta(db *sql.DB, key, value string) error {
+	_, err := db.Exec("INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)", key, value)
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// previewRebuildKey is the meta key of the fingerprint and start time of the last background rebuild
+const previewRebuildKey = "preview_rebuild"
+
+// rebuildRetryInterval is the time after which a background rebuild that has not finished is started again
+const rebuildRetryInterval = time.Minute
+
+// StartRebuild starts rebuilding the previews of all entries without waiting for it.
+// It is set by main; while it is nil, stale previews are only rendered for listed entries.
+var StartRebuild func() error
+
+// requestRebuild calls StartRebuild for stale previews,
+// unless a rebuild for the same settings was started recently.
+func requestRebuild(db *sql.DB, cfg *config.Config) {
+	if StartRebuild == nil {
+		return
+	}
+
+	fingerprint := preview.Fingerprint(cfg)
+	value, err := getMeta(db, previewRebuildKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if started, at, ok := strings.Cut(value, " "); ok && started == fingerprint {
+		if seconds, err := strconv.ParseInt(at, 10, 64); err == nil && time.Since(time.Unix(seconds, 0)) < rebuildRetryInterval {
+			return
+		}
+	}
+
+	if err := setMeta(db, previewRebuildKey, fmt.Sprintf("%s %d", fingerprint, time.Now().Unix())); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if err := StartRebuild(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to rebuild previews: %v\n", err)
+	}
+}
+
+// PreviewsStale reports whether the stored previews were generated with other preview settings.
+func PreviewsStale(db *sql.DB, cfg *config.Config) (bool, error) {
+	stored, err := getMeta(db, previewFingerprintKey)
+	if err != nil {
+		return false, err
+	}
+	return stored != preview.Fingerprint(cfg), nil
+}
+
+// rebuildBatchSize is the number of entries RebuildPreviews loads and writes at once.
+// Each batch is written in its own short transaction, so stores don't wait for the whole rebuild.
+var rebuildBatchSize = 100
+
+// previewUpdate is a regenerated preview waiting to be written
+type previewUpdate struct {
+	id          int
+	preview     string
+	contentType string
+}
+
+// RebuildPreviews regenerates the previews and content types of all entries with cfg and records its fingerprint.
+// If createIcons is true, missing icons of images are created, otherwise only existing icons are used.
+// Missing color swatches are always created.
+// Returns the number of updated previews.
+func RebuildPreviews(db *sql.DB, cfg *config.Config, createIcons bool) (int, error) {
+	updatedCount := 0
+	lastID := 0
+	for {
+		records, err := loadRecordBatch(db, lastID, rebuildBatchSize)
+		if err != nil {
+			return updatedCount, err
+		}
+		if len(records) == 0 {
+			break
+		}
+		lastID = records[len(records)-1].ID
+
+		// Icons and previews are generated before the transaction to keep it short
+		updates := make([]previewUpdate, len(records))
+		for i, e := range records {
+			bufferCfg := cfg.ForBuffer(e.Buffer)
+			classification := classify(e.Content, bufferCfg)
+			iconPath, hasIcon := entryIcon(e.ID, e.Content, classification, bufferCfg, createIcons)
+			updates[i] = previewUpdate{
+				id:          e.ID,
+				preview:     preview.GeneratePreviewClassified(e.PreviewEntry(hasIcon, iconPath), classification, cfg),
+				contentType: classification.Type,
+			}
+		}
+
+		if err := writePreviews(db, updates); err != nil {
+			return updatedCount, err
+		}
+		updatedCount += len(updates)
+	}
+
+	if err := setMeta(db, previewFingerprintKey, preview.Fingerprint(cfg)); err != nil {
+		return updatedCount, err
+	}
+	return updatedCount, nil
+}
+
+// writePreviews stores regenerated previews in one transaction.
+// Entries deleted since their preview was generated are skipped.
+func writePreviews(db *sql.DB, updates []previewUpdate) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateStmt, err := tx.Prepare("UPDATE clipboard SET preview = ?, content_type = ? WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare update statement: %w", err)
+	}
+	defer updateStmt.Close()
+
+	for _, u := range updates {
+		if _, err := updateStmt.Exec(u.preview, u.contentType, u.id); err != nil {
+			return fmt.Errorf("failed to update preview for id %d: %w", u.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit previews: %w", err)
+	}
+	return nil
+}
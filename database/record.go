@@ -0,0 +1,219 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"clipbox/config"
+	"clipbox/detect"
+	"clipbox/image"
+	"clipbox/preview"
+)
+
+// Record is a stored clipboard entry including its content
+type Record struct {
+	ID               int       `json:"id"`
+	Buffer           int       `json:"buffer"`
+	Pinned           bool      `json:"pinned"`
+	Content          []byte    `json:"content"`
+	Source           string    `json:"source"`
+	SourceApp        string    `json:"source_app"`
+	TemplateDisabled bool      `json:"template_disabled"`
+	MimeType         string    `json:"mime_type"`    // Type the content is copied back as, empty for text and images
+	ContentType      string    `json:"content_type"` // Type from detect.Classify, empty until the previews are rebuilt
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// recordColumns are the columns read by scanRecord, in order
+const recordColumns = "id, buffer_id, is_pinned, content, source, source_app, template_disabled, mime_type, content_type, created_at"
+
+// scanRecord reads a row selected with recordColumns
+func scanRecord(row interface{ Scan(dest ...any) error }) (*Record, error) {
+	var r Record
+	err := row.Scan(&r.ID, &r.Buffer, &r.Pinned, &r.Content, &r.Source, &r.SourceApp, &r.TemplateDisabled, &r.MimeType, &r.ContentType, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetRecord retrieves a clipboard entry by its ID
+func GetRecord(id int) (*Record, error) {
+	db, err := OpenDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return getRecord(db, id)
+}
+
+// getRecord retrieves a clipboard entry by its ID using an open database
+func getRecord(db *sql.DB, id int) (*Record, error) {
+	row := db.QueryRow("SELECT "+recordColumns+" FROM clipboard WHERE id = ?", id)
+	record, err := scanRecord(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry %d: %w", id, err)
+	}
+	return record, nil
+}
+
+// GetRecords retrieves clipboard entries by their IDs with a single query, in the order of ids
+func GetRecords(ids []int) ([]*Record, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	db, err := OpenDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return getRecords(db, ids)
+}
+
+// getRecords retrieves clipboard entries by their IDs using an open database
+func getRecords(db *sql.DB, ids []int) ([]*Record, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.Repeat("?,", len(ids))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	query := fmt.Sprintf("SELECT %s FROM clipboard WHERE id IN (%s)", recordColumns, placeholders)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entries: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[int]*Record, len(ids))
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		byID[record.ID] = record
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	records := make([]*Record, len(ids))
+	for i, id := range ids {
+		if records[i] = byID[id]; records[i] == nil {
+			return nil, fmt.Errorf("failed to get entry %d: %w", id, sql.ErrNoRows)
+		}
+	}
+	return records, nil
+}
+
+// loadRecordBatch retrieves up to limit clipboard entries with IDs greater than afterID, in ID order.
+// The rows are closed before it returns, so the caller can write to db.
+func loadRecordBatch(db *sql.DB, afterID, limit int) ([]*Record, error) {
+	rows, err := db.Query("SELECT "+recordColumns+" FROM clipboard WHERE id > ? ORDER BY id LIMIT ?", afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entries: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return records, nil
+}
+
+// PreviewEntry returns the fields of the record needed to render its preview
+func (r *Record) PreviewEntry(hasIcon bool, iconPath string) preview.Entry {
+	isPinned := 0
+	if r.Pinned {
+		isPinned = 1
+	}
+	return preview.Entry{
+		ID:               r.ID,
+		Buffer:           r.Buffer,
+		Content:          r.Content,
+		IsPinned:         isPinned,
+		HasIcon:          hasIcon,
+		IconPath:         iconPath,
+		Source:           r.Source,
+		SourceApp:        r.SourceApp,
+		TemplateDisabled: r.TemplateDisabled,
+	}
+}
+
+// classify classifies content with the secret detection settings of cfg
+func classify(content []byte, cfg *config.Config) detect.Classification {
+	return detect.NewClassifier(cfg.PasswordIgnorePatterns, cfg.SecretThreshold).Classify(content)
+}
+
+// entryIcon returns the icon file of an image or color entry, if icons are enabled for its type.
+// Missing image icons are created only if create is true, missing color swatches always.
+func entryIcon(id int, content []byte, classification detect.Classification, cfg *config.Config, create bool) (string, bool) {
+	switch {
+	case classification.Type == detect.TypeImage && cfg.ShowImageIcons:
+	case classification.Type == detect.TypeColor && (cfg.ShowImageIcons || cfg.ShowTypeIcons):
+		create = true
+	default:
+		return "", false
+	}
+
+	if path, ok := image.GetIconPath(id); ok {
+		return path, true
+	}
+	if !create {
+		return "", false
+	}
+
+	var path string
+	var err error
+	if classification.Type == detect.TypeColor {
+		c, _ := detect.ParseColor(detect.TextContent(content))
+		path, err = image.ProcessColorIcon(id, c)
+	} else {
+		path, err = image.ProcessImageIcon(id, content)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create icon for id %d: %v\n", id, err)
+		return "", false
+	}
+	return path, true
+}
+
+// updatePreview regenerates the preview and content type of an entry from its stored fields
+func updatePreview(db *sql.DB, id int, cfg *config.Config) error {
+	record, err := getRecord(db, id)
+	if err != nil {
+		return err
+	}
+
+	bufferCfg := cfg.ForBuffer(record.Buffer)
+	classification := classify(record.Content, bufferCfg)
+	iconPath, hasIcon := entryIcon(id, record.Content, classification, bufferCfg, false)
+
+	previewText := preview.GeneratePreviewClassified(record.PreviewEntry(hasIcon, iconPath), classification, cfg)
+	if _, err := db.Exec("UPDATE clipboard SET preview = ?, content_type = ? WHERE id = ?", previewText, classification.Type, id); err != nil {
+		return fmt.Errorf("failed to update preview: %w", err)
+	}
+	return nil
+}
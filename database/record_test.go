@@ -0,0 +1,38 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"testing"
+
+	"clipbox/utils"
+)
+
+func TestGetRecords(t *testing.T) {
+	testEnv(t, "")
+	for _, content := range []string{"one", "two", "three"} {
+		if err := StoreContent([]byte(content), utils.SourceClipboard, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := GetRecords([]int{3, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range records {
+		got = append(got, string(r.Content))
+	}
+	if len(got) != 3 || got[0] != "three" || got[1] != "one" || got[2] != "two" {
+		t.Errorf("GetRecords(3, 1, 2) = %q, want three, one, two", got)
+	}
+
+	if records, err := GetRecords(nil); err != nil || records != nil {
+		t.Errorf("GetRecords(nil) = %v, %v, want no records", records, err)
+	}
+	if _, err := GetRecords([]int{1, 42}); err == nil {
+		t.Error("GetRecords with a missing entry succeeded, want an error")
+	}
+}
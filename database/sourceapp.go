@@ -0,0 +1,83 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"clipbox/config"
+	"clipbox/detect"
+	"clipbox/utils"
+)
+
+const sourceAppTimeout = 500 * time.Millisecond
+
+// Capture is what is known about copied content in the session it was copied in
+type Capture struct {
+	App      string // Focused application, see DetectSourceApp
+	MimeType string // detect.FileListType if the selection offered a file list, otherwise empty
+}
+
+// DetectCapture detects the source application and whether a file list was copied as files.
+// Clients of a clipbox server call it themselves, the server runs outside the user's session.
+func DetectCapture(content []byte, source string) Capture {
+	capture := Capture{App: DetectSourceApp()}
+	// Other content is copied back as text or with the type wl-copy detects
+	if _, ok := detect.ParseFileList(content); ok && utils.SelectionOffers(detect.FileListType, source == utils.SourcePrimary) {
+		capture.MimeType = detect.FileListType
+	}
+	return capture
+}
+
+// DetectSourceApp returns the id of the focused application using source_app_command.
+func DetectSourceApp() string {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return ""
+	}
+	return detectSourceApp(cfg.SourceAppCommand)
+}
+
+// detectSourceApp runs the configured command to get the id of the focused application.
+// Returns an empty string if no command is configured or it fails.
+func detectSourceApp(command string) string {
+	if command == "" {
+		return ""
+	}
+
+	output, err := utils.RunCommand(command, nil, sourceAppTimeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: source_app_command failed: %v\n", err)
+		return ""
+	}
+
+	// Only the first line is used, compositors may print more details
+	app, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(app)
+}
+
+// isIgnoredApp checks if an application id matches any of the ignore_app patterns.
+// Patterns are case-insensitive globs (e.g. "org.keepassxc.*").
+func isIgnoredApp(app string, patterns []string) bool {
+	if app == "" {
+		return false
+	}
+
+	app = strings.ToLower(app)
+	for _, pattern := range patterns {
+		matched, err := path.Match(strings.ToLower(pattern), app)
+		if err != nil {
+			// Skip invalid patterns
+			continue
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"clipbox/utils"
+)
+
+// testEnv points the config and the database to a temporary directory and writes config as config.conf.
+func testEnv(t *testing.T, config string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	configDir := filepath.Join(dir, "config", "clipbox")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.conf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectSourceApp(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{"no command", "", ""},
+		{"stub", "echo firefox", "firefox"},
+		{"first line only", "printf ' org.gnome.Nautilus \\nclass: x\\n'", "org.gnome.Nautilus"},
+		{"failing command", "echo firefox; exit 1", ""},
+		{"missing command", "clipbox-no-such-command", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectSourceApp(tt.command); got != tt.want {
+				t.Errorf("detectSourceApp(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectSourceAppTimeout(t *testing.T) {
+	start := time.Now()
+	if got := detectSourceApp("sleep 5; echo firefox"); got != "" {
+		t.Errorf("detectSourceApp of a slow command = %q, want empty", got)
+	}
+	if elapsed := time.Since(start); elapsed > sourceAppTimeout+time.Second {
+		t.Errorf("slow command took %s, want about %s", elapsed, sourceAppTimeout)
+	}
+}
+
+func TestIsIgnoredApp(t *testing.T) {
+	patterns := []string{"org.keepassxc.*", "Bitwarden", "[invalid"}
+	tests := []struct {
+		app  string
+		want bool
+	}{
+		{"org.keepassxc.KeePassXC", true},
+		{"ORG.KEEPASSXC.KeePassXC", true},
+		{"bitwarden", true},
+		{"BITWARDEN", true},
+		{"bitwarden-desktop", false},
+		{"org.keepassxc", false},
+		{"firefox", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isIgnoredApp(tt.app, patterns); got != tt.want {
+			t.Errorf("isIgnoredApp(%q) = %v, want %v", tt.app, got, tt.want)
+		}
+	}
+}
+
+func TestStoreSourceApp(t *testing.T) {
+	testEnv(t, "source_app_command=cat $XDG_CONFIG_HOME/app\nignore_app=org.keepassxc.*\n")
+	appFile := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "app")
+	store := func(app, content string) {
+		t.Helper()
+		if err := os.WriteFile(appFile, []byte(app+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := StoreContent([]byte(content), utils.SourceClipboard, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store("Firefox", "from firefox")
+	store("org.keepassxc.KeePassXC", "secret from keepassxc")
+	store("kitty", "from kitty")
+
+	tests := []struct {
+		app  string
+		want []string
+	}{
+		{"", []string{"from kitty", "from firefox"}},
+		{"firefox", []string{"from firefox"}},
+		{"FIREFOX", []string{"from firefox"}},
+		{"KITTY", []string{"from kitty"}},
+		{"org.keepassxc.KeePassXC", nil},
+		{"fire", nil},
+	}
+	for _, tt := range tests {
+		listing, err := LoadListing(0, tt.app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range listing.Entries {
+			got = append(got, e.Text)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("LoadListing(app=%q) = %q, want %q", tt.app, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !strings.Contains(got[i], tt.want[i]) {
+				t.Errorf("LoadListing(app=%q) = %q, want %q", tt.app, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDetectCapture(t *testing.T) {
+	testEnv(t, "")
+	dir := t.TempDir()
+	// The clipboard offers a file list, the primary selection only text
+	script := "#!/bin/sh\n[ \"$2\" = --primary ] && echo text/plain && exit 0\nprintf 'x-special/gnome-copied-files\\ntext/uri-list\\ntext/plain\\n'\n"
+	if err := os.WriteFile(filepath.Join(dir, "wl-paste"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	fileList := []byte("file:///home/user/notes.txt")
+	tests := []struct {
+		name    string
+		content []byte
+		source  string
+		want    string
+	}{
+		{"copied files", fileList, utils.SourceClipboard, "text/uri-list"},
+		{"file uri selected as text", fileList, utils.SourcePrimary, ""},
+		{"text", []byte("notes.txt"), utils.SourceClipboard, ""},
+	}
+	for _, tt := range tests {
+		if got := DetectCapture(tt.content, tt.source).MimeType; got != tt.want {
+			t.Errorf("%s: DetectCapture().MimeType = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// The offered type is kept with the entry
+	if err := StoreContent(fileList, utils.SourceClipboard, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := StoreContent(fileList, utils.SourcePrimary, 0); err != nil {
+		t.Fatal(err)
+	}
+	records, err := GetRecords([]int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0].MimeType != "text/uri-list" || records[1].MimeType != "" {
+		t.Errorf("stored mime types = %q, %q, want text/uri-list and empty", records[0].MimeType, records[1].MimeType)
+	}
+}
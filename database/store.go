@@ -8,65 +8,143 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
-	"os"
 	"strings"
+	"time"
 
 	"clipbox/config"
+	"clipbox/detect"
 	"clipbox/image"
 	"clipbox/preview"
+	"clipbox/utils"
 )
 
 const maxFileSize = 12 * 1e6 // 12MB
 
-// Store reads content from stdin and saves it to the clipboard database.
-// Handles deduplication, icon generation for images, and max items limit.
-func Store() error {
-	limitedReader := io.LimitReader(os.Stdin, maxFileSize+1)
+// Store reads content from r (usually stdin) and saves it to the clipboard database.
+// Source is the selection the content was captured from (utils.SourceClipboard or utils.SourcePrimary).
+// A bufferID of 0 stores to the current buffer.
+func Store(r io.Reader, source string, bufferID int) error {
+	content, err := ReadContent(r)
+	if err != nil {
+		return err
+	}
+	return StoreContent(content, source, bufferID)
+}
+
+// ReadContent reads clipboard content from r.
+// Content larger than the storage limit is read only up to one byte past the limit,
+// which is enough for StoreContent to reject it.
+func ReadContent(r io.Reader) ([]byte, error) {
+	limitedReader := io.LimitReader(r, maxFileSize+1)
 	content, err := io.ReadAll(limitedReader)
 	if err != nil {
-		return fmt.Errorf("failed to read stdin: %w", err)
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return content, nil
+}
+
+// StoreContent saves content to a buffer of the clipboard database, 0 is the current buffer.
+// The source application and the offered type are detected in the current session.
+func StoreContent(content []byte, source string, bufferID int) error {
+	return StoreCaptured(content, source, bufferID, DetectCapture(content, source))
+}
+
+// StoreCaptured saves content to a buffer of the clipboard database, 0 is the current buffer.
+// Handles deduplication, icon generation for images, and max items limit.
+// Primary selection entries use their own retention settings.
+func StoreCaptured(content []byte, source string, bufferID int, capture Capture) error {
+	if bufferID < 0 || bufferID > 5 {
+		return fmt.Errorf("invalid buffer ID: %d (must be 1-5)", bufferID)
 	}
+
+	switch source {
+	case "":
+		source = utils.SourceClipboard
+	case utils.SourceClipboard, utils.SourcePrimary:
+	default:
+		return fmt.Errorf("unknown source: %s", source)
+	}
+
 	if len(content) > maxFileSize {
 		return nil
 	}
 
-	trimmedContent := bytes.TrimSpace(content)
-	if len(trimmedContent) == 0 {
+	if len(bytes.TrimSpace(content)) == 0 {
 		return nil
 	}
 
-	// Load config to check MinStoreLength
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if cfg.MinStoreLength > 0 && len(trimmedContent) < cfg.MinStoreLength {
-		return nil
-	}
-
 	db, err := OpenDB()
 	if err != nil {
 		return err
 	}
 	defer db.Close()
 
-	currentBuffer, err := GetCurrentBuffer(db)
-	if err != nil {
-		return fmt.Errorf("failed to get current buffer: %w", err)
+	currentBuffer := bufferID
+	if currentBuffer == 0 {
+		currentBuffer, err = GetCurrentBuffer(db)
+		if err != nil {
+			return fmt.Errorf("failed to get current buffer: %w", err)
+		}
+	}
+	cfg = cfg.ForBuffer(currentBuffer)
+
+	if isIgnoredApp(capture.App, cfg.IgnoreApps) {
+		return nil
 	}
 
-	// Find and delete duplicates (keep their IDs to delete icon files)
+	hookTimeout := time.Duration(cfg.HookTimeout) * time.Millisecond
+	content, ok := runPreStoreHook(cfg.PreStoreHook, content, hookTimeout, cfg.PreStoreHookOnFailure)
+	if !ok || len(content) > maxFileSize {
+		return nil
+	}
+
+	// The pre-store hook may have rewritten the content, so length checks come after it
+	trimmedContent := bytes.TrimSpace(content)
+	if len(trimmedContent) == 0 {
+		return nil
+	}
+
+	// Text in UTF-16 or a legacy encoding is decoded first, and stored as UTF-8 if it had CRLF line endings
+	if cfg.NormalizeLineEndings {
+		if text, _, ok := detect.DecodeText(content); ok && strings.Contains(text, "\r\n") {
+			content = []byte(utils.NormalizeLineEndings(text))
+		}
+	}
+
+	if cfg.IgnoreCredentials {
+		if _, isCredential := detect.DetectCredential(content); isCredential {
+			return nil
+		}
+	}
+
+	minStoreLength := cfg.MinStoreLength
+	if source == utils.SourcePrimary {
+		minStoreLength = cfg.PrimaryMinStoreLength
+	}
+
+	if minStoreLength > 0 && len(trimmedContent) < minStoreLength {
+		return nil
+	}
+
+	// Find and delete duplicates from the same selection (keep their IDs to delete icon files).
+	// The same text in the clipboard and the primary selection stays two entries,
+	// so each keeps its source marker and counts towards its own max_items limit.
 	getDuplicatesQuery := `
     SELECT id FROM clipboard
     WHERE buffer_id = ?
     AND content = ?
+    AND source = ?
     AND is_pinned = 0
     ORDER BY id DESC
     LIMIT ?
     `
 
-	dupRows, err := db.Query(getDuplicatesQuery, currentBuffer, content, cfg.MaxDedupeSearch)
+	dupRows, err := db.Query(getDuplicatesQuery, currentBuffer, content, source, cfg.MaxDedupeSearch)
 	if err != nil {
 		return fmt.Errorf("failed to get duplicates: %w", err)
 	}
@@ -87,15 +165,16 @@ func Store() error {
 		placeholders = placeholders[:len(placeholders)-1]
 
 		deleteQuery := fmt.Sprintf(
-			"DELETE FROM clipboard WHERE buffer_id = ? AND content = ? AND is_pinned = 0 AND id IN (%s)",
+			"DELETE FROM clipboard WHERE buffer_id = ? AND content = ? AND source = ? AND is_pinned = 0 AND id IN (%s)",
 			placeholders,
 		)
 
-		args := make([]interface{}, len(duplicateIDs)+2)
+		args := make([]interface{}, len(duplicateIDs)+3)
 		args[0] = currentBuffer
 		args[1] = content
+		args[2] = source
 		for i, id := range duplicateIDs {
-			args[i+2] = id
+			args[i+3] = id
 		}
 
 		_, err = db.Exec(deleteQuery, args...)
@@ -108,11 +187,13 @@ func Store() error {
 		}
 	}
 
+	classification := classify(content, cfg)
+
 	insertQuery := `
-    INSERT INTO clipboard (buffer_id, content, preview) VALUES (?, ?, ?)
+    INSERT INTO clipboard (buffer_id, content, preview, source, source_app, mime_type, content_type) VALUES (?, ?, ?, ?, ?, ?, ?)
     `
 
-	result, err := db.Exec(insertQuery, currentBuffer, content, "")
+	result, err := db.Exec(insertQuery, currentBuffer, content, "", source, capture.App, capture.MimeType, classification.Type)
 	if err != nil {
 		return fmt.Errorf("failed to insert: %w", err)
 	}
@@ -122,38 +203,54 @@ func Store() error {
 		return fmt.Errorf("failed to get inserted ID: %w", err)
 	}
 
-	var hasIcon bool
-	var iconPath string
-	if cfg.ShowImageIcons {
-		if _, isImage := image.DetectImageFormat(content); isImage {
-			path, err := image.ProcessImageIcon(int(insertedID), content)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to process image icon: %v\n", err)
-			} else {
-				hasIcon = true
-				iconPath = path
-			}
-		}
-	}
-
-	previewText := preview.GeneratePreview(int(insertedID), content, 0, hasIcon, iconPath, cfg)
+	iconPath, hasIcon := entryIcon(int(insertedID), content, classification, cfg, true)
+
+	previewText := preview.GeneratePreviewClassified(preview.Entry{
+		ID:        int(insertedID),
+		Buffer:    currentBuffer,
+		Content:   content,
+		HasIcon:   hasIcon,
+		IconPath:  iconPath,
+		Source:    source,
+		SourceApp: capture.App,
+	}, classification, cfg)
 	_, err = db.Exec("UPDATE clipboard SET preview = ? WHERE id = ?", previewText, insertedID)
 	if err != nil {
 		return fmt.Errorf("failed to update preview: %w", err)
 	}
 
-	if cfg.MaxItems > 0 {
-		if err := EnforceMaxItems(db, currentBuffer, cfg.MaxItems); err != nil {
-			return fmt.Errorf("failed to enforce max_items limit: %w", err)
-		}
+	if err := EnforceMaxItems(db, cfg, currentBuffer, source); err != nil {
+		return fmt.Errorf("failed to enforce max_items limit: %w", err)
 	}
 
+	runPostStoreHook(cfg.PostStoreHook, postStoreEvent{
+		ID:        int(insertedID),
+		Buffer:    currentBuffer,
+		Type:      classification.Type,
+		Size:      len(content),
+		Source:    source,
+		SourceApp: capture.App,
+	})
+
 	return nil
 }
 
-// EnforceMaxItems removes oldest unpinned entries exceeding maxItems limit.
+// EnforceMaxItems removes oldest unpinned entries of a source exceeding the max_items limit
+// of the buffer, primary_max_items for the primary selection, and entries older than max_age.
 // Pinned entries are always kept and don't count towards the limit.
-func EnforceMaxItems(db *sql.DB, bufferID int, maxItems int) error {
+func EnforceMaxItems(db *sql.DB, cfg *config.Config, bufferID int, source string) error {
+	cfg = cfg.ForBuffer(bufferID)
+
+	if cfg.MaxAge > 0 {
+		if err := deleteExpired(db, bufferID, cfg.MaxAge); err != nil {
+			return err
+		}
+	}
+
+	maxItems := cfg.MaxItems
+	if source == utils.SourcePrimary {
+		maxItems = cfg.PrimaryMaxItems
+	}
 	if maxItems <= 0 {
 		return nil
 	}
@@ -161,16 +258,17 @@ func EnforceMaxItems(db *sql.DB, bufferID int, maxItems int) error {
 	getIdsToDeleteQuery := `
 		SELECT id FROM clipboard
 		WHERE buffer_id = ?
+		AND source = ?
 		AND is_pinned = 0
 		AND id NOT IN (
 			SELECT id FROM clipboard
-			WHERE buffer_id = ? AND is_pinned = 0
+			WHERE buffer_id = ? AND source = ? AND is_pinned = 0
 			ORDER BY id DESC
 			LIMIT ?
 		)
 	`
 
-	rows, err := db.Query(getIdsToDeleteQuery, bufferID, bufferID, maxItems)
+	rows, err := db.Query(getIdsToDeleteQuery, bufferID, source, bufferID, source, maxItems)
 	if err != nil {
 		return fmt.Errorf("failed to query entries to delete: %w", err)
 	}
@@ -216,3 +314,40 @@ func EnforceMaxItems(db *sql.DB, bufferID int, maxItems int) error {
 
 	return nil
 }
+
+// deleteExpired removes unpinned entries of a buffer older than maxAge.
+func deleteExpired(db *sql.DB, bufferID int, maxAge time.Duration) error {
+	rows, err := db.Query(
+		"SELECT id FROM clipboard WHERE buffer_id = ? AND is_pinned = 0 AND created_at < datetime('now', ?)",
+		bufferID, ageModifier(maxAge),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query expired entries: %w", err)
+	}
+	defer rows.Close()
+
+	var expiredIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		expiredIDs = append(expiredIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	for _, id := range expiredIDs {
+		if _, err := db.Exec("DELETE FROM clipboard WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete expired entry: %w", err)
+		}
+		_ = image.DeleteIconFile(id)
+	}
+	return nil
+}
+
+// ageModifier returns the SQLite datetime modifier subtracting maxAge
+func ageModifier(maxAge time.Duration) string {
+	return fmt.Sprintf("-%d seconds", int64(maxAge.Seconds()))
+}
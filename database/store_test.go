@@ -0,0 +1,40 @@
+package database
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"testing"
+
+	"clipbox/utils"
+)
+
+func TestStoreNormalizeLineEndings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"utf-8", "one\r\ntwo\r\n", "one\ntwo\n"},
+		{"utf-16le with bom", "\xff\xfeo\x00n\x00e\x00\r\x00\n\x00t\x00w\x00o\x00", "one\ntwo"},
+		{"utf-16le without bom", "o\x00n\x00e\x00\r\x00\n\x00t\x00w\x00o\x00", "one\ntwo"},
+		{"windows-1252", "caf\xe9\r\nna\xefve", "café\nnaïve"},
+		{"utf-16le without crlf", "\xff\xfeo\x00n\x00e\x00", "\xff\xfeo\x00n\x00e\x00"},
+		{"binary", "\x89PNG\r\n\x1a\n\x00\x00", "\x89PNG\r\n\x1a\n\x00\x00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testEnv(t, "normalize_line_endings=true\n")
+			if err := StoreContent([]byte(tt.content), utils.SourceClipboard, 0); err != nil {
+				t.Fatal(err)
+			}
+			record, err := GetRecord(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(record.Content) != tt.want {
+				t.Errorf("stored %q, want %q", record.Content, tt.want)
+			}
+		})
+	}
+}
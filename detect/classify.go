@@ -0,0 +1,195 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"clipbox/image"
+)
+
+// Classification is the result of Classify: the primary type of content and its other traits
+type Classification struct {
+	Type        string       // One of the Type constants
+	MultiLine   bool         // Text with line breaks after trimming
+	Binary      bool         // Content is not valid UTF-8, or a file format detected by DetectBinaryFormat
+	ImageFormat string       // Format detected by image.DetectImageFormat, empty if content is no image
+	Format      BinaryFormat // Format of TypeBinary content detected by DetectBinaryFormat, zero if unknown
+	Encoding    string       // Encoding of text decoded by DecodeText, empty for UTF-8
+	Language    string       // Language of a script from its shebang line or syntax, such as "python" or "bash"
+	Files       []string     // Local paths of a TypeFile uri-list
+	SecretKind  string       // Kind of TypePassword and TypeCredential content, such as "github-token" or "ssh-private-key"
+	Confidence  float64      // Secret confidence of TypePassword content
+}
+
+// Classifier classifies content with the secret detection settings of a buffer
+type Classifier struct {
+	ignorePatterns  []*regexp.Regexp
+	secretThreshold float64
+}
+
+// NewClassifier returns a classifier that reports secrets with at least secretThreshold confidence
+// as TypePassword, unless they match one of ignorePatterns. Invalid patterns are skipped.
+func NewClassifier(ignorePatterns []string, secretThreshold float64) *Classifier {
+	return &Classifier{
+		ignorePatterns:  compilePatterns(ignorePatterns),
+		secretThreshold: secretThreshold,
+	}
+}
+
+// Classify returns the type of content, checking the most specific types first.
+func (c *Classifier) Classify(content []byte) Classification {
+	if format, isImage := image.DetectImageFormat(content); isImage {
+		return Classification{Type: TypeImage, Binary: !utf8.Valid(content), ImageFormat: format}
+	}
+	// Text may start like a magic number by chance, but can't contain NUL bytes
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) != -1 {
+		if format, ok := DetectBinaryFormat(content); ok {
+			return Classification{Type: TypeBinary, Binary: true, Format: format}
+		}
+	}
+	// UTF-16 and legacy encodings are classified by their decoded text
+	if text, encoding, ok := DecodeText(content); ok && encoding != "" {
+		result := c.Classify([]byte(text))
+		result.Encoding = encoding
+		return result
+	}
+	if !utf8.Valid(content) {
+		return Classification{Type: TypeBinary, Binary: true}
+	}
+
+	text := strings.TrimSpace(string(content))
+	result := Classification{
+		Type:      TypeText,
+		MultiLine: strings.ContainsAny(text, "\r\n"),
+		Language:  shebangLanguage(text),
+	}
+	if text == "" {
+		return result
+	}
+
+	if kind, ok := detectCredential(text); ok {
+		result.Type = TypeCredential
+		result.SecretKind = kind
+		return result
+	}
+
+	if files, ok := parseFileList(text); ok {
+		result.Type = TypeFile
+		result.Files = files
+		return result
+	}
+
+	switch {
+	case isJSON(text):
+		result.Type = TypeJSON
+	case markupType(text) != "":
+		result.Type = markupType(text)
+	case isSQL(text):
+		result.Type = TypeSQL
+	case result.Language != "" && result.MultiLine:
+		result.Type = TypeCode
+	case result.MultiLine:
+		// URLs, paths and the other single-value types never span lines
+		if language := codeLanguage(text); language != "" {
+			result.Type = TypeCode
+			result.Language = language
+		} else if isYAML(text) {
+			result.Type = TypeYAML
+		}
+	case isURL(text):
+		result.Type = TypeURL
+	case isEmail(text):
+		result.Type = TypeEmail
+	case isIP(text):
+		result.Type = TypeIP
+	case isUUID(text):
+		result.Type = TypeUUID
+	case isColor(text):
+		result.Type = TypeColor
+	case isDateTime(text):
+		result.Type = TypeDateTime
+	case isFilePath(text):
+		result.Type = TypePath
+	default:
+		// Secrets are single words, see isSecretCandidate
+		secret := classifySecret(text, c.ignorePatterns)
+		if secret.Kind != "" && secret.Confidence >= c.secretThreshold {
+			result.Type = TypePassword
+			result.SecretKind = secret.Kind
+			result.Confidence = secret.Confidence
+		}
+	}
+	return result
+}
+
+// languageExtensions are the file extensions of the languages set by Classify, where they differ from the name
+var languageExtensions = map[string]string{
+	"python": "py", "javascript": "js", "rust": "rs", "ruby": "rb",
+	"bash": "sh", "sh": "sh", "zsh": "sh", "node": "js", "perl": "pl",
+}
+
+// Extension returns the file extension for content of the classification without the dot,
+// such as "png", "pdf", "json" or "py". Unknown binary data is "bin", other text "txt".
+// Executables without an extension return "".
+func (c Classification) Extension() string {
+	switch c.Type {
+	case TypeImage:
+		return c.ImageFormat
+	case TypeBinary:
+		if c.Format.Name == "" {
+			return "bin"
+		}
+		return c.Format.Extension
+	case TypeJSON, TypeYAML, TypeXML, TypeHTML, TypeSQL:
+		return c.Type
+	case TypeCode:
+		if ext, ok := languageExtensions[c.Language]; ok {
+			return ext
+		}
+		if c.Language != "" {
+			return c.Language
+		}
+	}
+	return "txt"
+}
+
+// shebangRegex matches the interpreter of a script, with or without env
+var shebangRegex = regexp.MustCompile(`^#!\s*(?:/\S*/env\s+(?:-\S+\s+)*)?(\S+)`)
+
+// shebangLanguage returns the interpreter of a script without its path and version, such as python for /usr/bin/python3
+func shebangLanguage(text string) string {
+	match := shebangRegex.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimRight(path.Base(match[1]), "0123456789.")
+}
+
+// patternCache holds compiled ignore patterns by their source, nil for invalid ones
+var patternCache sync.Map
+
+// compilePatterns compiles regular expressions once per process, skipping invalid ones
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		cached, ok := patternCache.Load(pattern)
+		if !ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				re = nil
+			}
+			cached, _ = patternCache.LoadOrStore(pattern, re)
+		}
+		if re := cached.(*regexp.Regexp); re != nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
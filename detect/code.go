@@ -0,0 +1,111 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import "regexp"
+
+// minLanguageScore is the number of distinct patterns of a language that must match
+const minLanguageScore = 2
+
+// languagePatterns are the syntax patterns of common programming languages, checked in order.
+// Each pattern counts once, the language with the most matching patterns wins.
+var languagePatterns = []struct {
+	name     string
+	patterns []*regexp.Regexp
+}{
+	{"go", compileAll(
+		`(?m)^package \w+$`,
+		`(?m)^func (\(\w+ \*?\w+\) )?\w+\(`,
+		`(?m)^import (\($|")`,
+		`\w+ := `,
+		`\berr != nil\b`,
+		`\bfmt\.\w+\(`,
+	)},
+	{"python", compileAll(
+		`(?m)^\s*def \w+\(.*\)( -> .+)?:\s*$`,
+		`(?m)^\s*class \w+(\(.*\))?:\s*$`,
+		`(?m)^(from [\w.]+ )?import \w+`,
+		`(?m)^\s*(if|elif|for|while|with|try|except|else)\b.*:\s*$`,
+		`\bself\.\w`,
+		`\bprint\(`,
+		`(?m)^if __name__ == `,
+	)},
+	{"rust", compileAll(
+		`(?m)^\s*(pub )?fn \w+`,
+		`\blet (mut )?\w+(: [\w<>&]+)? = `,
+		`(?m)^use \w+(::\w+)+`,
+		`(?m)^\s*impl\b`,
+		`\b(println|format|vec)!\(`,
+	)},
+	{"javascript", compileAll(
+		`\b(const|let|var) \w+ = `,
+		`\bfunction\s*\w*\(`,
+		`\) => `,
+		`\bconsole\.\w+\(`,
+		`\brequire\(['"]`,
+		`(?m)^import .+ from ['"]`,
+		`(?m)^export (default |const |function |class )`,
+		` === `,
+	)},
+	{"java", compileAll(
+		`(?m)^\s*(public|private|protected) (static )?(final )?(class|interface|void|[\w<>\[\]]+) \w+`,
+		`\bSystem\.out\.print`,
+		`(?m)^import java\.`,
+		`(?m)^package [\w.]+;$`,
+		`@Override\b`,
+	)},
+	{"c", compileAll(
+		`(?m)^#include\s*[<"]`,
+		`\bint main\(`,
+		`\bprintf\(`,
+		`(?m)^#define \w`,
+		`\bstruct \w+\s*\{`,
+		`(?m)^(static )?(void|int|char|unsigned|size_t) \*?\w+\(`,
+	)},
+	{"php", compileAll(
+		`^<\?php\b`,
+		`\$\w+ = `,
+		`\$this->`,
+		`(?m)^\s*echo `,
+	)},
+	{"ruby", compileAll(
+		`(?m)^\s*def \w+[?!]?(\(.*\))?\s*$`,
+		`(?m)^\s*end\s*$`,
+		`(?m)^\s*puts `,
+		`(?m)^require ['"]`,
+		`\.each do \|`,
+	)},
+	{"css", compileAll(
+		`(?m)^[\w.#:*\[\]="' >,-]+\s*\{\s*$`,
+		`(?m)^\s*[\w-]+\s*:\s*[^;{}]+;\s*$`,
+		`(?m)^@(media|import|keyframes)\b`,
+	)},
+}
+
+// compileAll compiles regular expressions known to be valid
+func compileAll(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		compiled[i] = regexp.MustCompile(pattern)
+	}
+	return compiled
+}
+
+// codeLanguage guesses the programming language of trimmed text from its syntax.
+// Returns "" if no language has enough matching patterns.
+func codeLanguage(text string) string {
+	best, bestScore := "", minLanguageScore-1
+	for _, language := range languagePatterns {
+		score := 0
+		for _, pattern := range language.patterns {
+			if pattern.MatchString(text) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = language.name, score
+		}
+	}
+	return best
+}
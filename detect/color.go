@@ -0,0 +1,179 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/hex"
+	"image/color"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// hexColorRegex matches hex colors as #rrggbb or #rrggbbaa.
+	// The short forms #rgb and #rgba are left out, they are mostly issue numbers or hashtags such as #123 or #add.
+	hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	// colorFuncRegex matches CSS color functions such as rgb(30, 144, 255) or hsl(210deg 100% 56% / 50%)
+	colorFuncRegex = regexp.MustCompile(`(?i)^(rgba?|hsla?)\(\s*([^()]*?)\s*\)$`)
+)
+
+// IsColor determines if content is a color.
+func IsColor(content []byte) bool {
+	text, ok := validateAndTrim(content)
+	return ok && isColor(text)
+}
+
+// isColor checks trimmed text
+func isColor(text string) bool {
+	_, ok := parseColor(text)
+	return ok
+}
+
+// ParseColor parses a CSS color in #rrggbb, rgb() or hsl() notation, with or without alpha.
+func ParseColor(content []byte) (color.NRGBA, bool) {
+	text, ok := validateAndTrim(content)
+	if !ok {
+		return color.NRGBA{}, false
+	}
+	return parseColor(text)
+}
+
+// parseColor parses trimmed text
+func parseColor(text string) (color.NRGBA, bool) {
+	if hexColorRegex.MatchString(text) {
+		return parseHexColor(text[1:]), true
+	}
+
+	match := colorFuncRegex.FindStringSubmatch(text)
+	if match == nil {
+		return color.NRGBA{}, false
+	}
+	args, alpha, ok := colorArgs(match[2])
+	if !ok {
+		return color.NRGBA{}, false
+	}
+
+	a := 1.0
+	if alpha != "" {
+		if a, ok = parseFraction(alpha, 1); !ok {
+			return color.NRGBA{}, false
+		}
+	}
+
+	var r, g, b float64
+	if strings.HasPrefix(strings.ToLower(match[1]), "rgb") {
+		var rgb [3]float64
+		for i, arg := range args {
+			if rgb[i], ok = parseFraction(arg, 255); !ok {
+				return color.NRGBA{}, false
+			}
+		}
+		r, g, b = rgb[0], rgb[1], rgb[2]
+	} else {
+		h, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(args[0]), "deg"), 64)
+		if err != nil {
+			return color.NRGBA{}, false
+		}
+		s, okS := parsePercent(args[1])
+		l, okL := parsePercent(args[2])
+		if !okS || !okL {
+			return color.NRGBA{}, false
+		}
+		r, g, b = hslToRGB(h, s, l)
+	}
+
+	return color.NRGBA{R: toByte(r), G: toByte(g), B: toByte(b), A: toByte(a)}, true
+}
+
+// colorArgs splits the arguments of a color function in comma or space syntax into
+// three components and the alpha value, which is empty if there is none
+func colorArgs(inner string) ([]string, string, bool) {
+	main, alpha, hasSlash := strings.Cut(inner, "/")
+	var args []string
+	if strings.Contains(main, ",") {
+		for _, arg := range strings.Split(main, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	} else {
+		args = strings.Fields(main)
+	}
+
+	if hasSlash {
+		alpha = strings.TrimSpace(alpha)
+		return args, alpha, len(args) == 3 && alpha != ""
+	}
+	if len(args) == 4 {
+		return args[:3], args[3], true
+	}
+	return args, "", len(args) == 3
+}
+
+// parseHexColor parses 6 or 8 hex digits without the leading #
+func parseHexColor(digits string) color.NRGBA {
+	if len(digits) == 6 {
+		digits += "ff"
+	}
+	b, _ := hex.DecodeString(digits)
+	return color.NRGBA{R: b[0], G: b[1], B: b[2], A: b[3]}
+}
+
+// parseFraction parses a number from 0 to max or a percentage, returning a value from 0 to 1
+func parseFraction(arg string, max float64) (float64, bool) {
+	if strings.HasSuffix(arg, "%") {
+		return parsePercent(arg)
+	}
+	x, err := strconv.ParseFloat(arg, 64)
+	if err != nil || x < 0 || x > max {
+		return 0, false
+	}
+	return x / max, true
+}
+
+// parsePercent parses a percentage from 0 to 100, the % sign is optional
+func parsePercent(arg string) (float64, bool) {
+	x, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+	if err != nil || x < 0 || x > 100 {
+		return 0, false
+	}
+	return x / 100, true
+}
+
+// hslToRGB converts a hue in degrees and saturation and lightness from 0 to 1 to RGB from 0 to 1
+func hslToRGB(h, s, l float64) (float64, float64, float64) {
+	h = math.Mod(math.Mod(h, 360)+360, 360) / 360
+	if s == 0 {
+		return l, l, l
+	}
+	q := l * (1 + s)
+	if l >= 0.5 {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	return hueToRGB(p, q, h+1.0/3), hueToRGB(p, q, h), hueToRGB(p, q, h-1.0/3)
+}
+
+func hueToRGB(p, q, t float64) float64 {
+	if t < 0 {
+		t++
+	}
+	if t > 1 {
+		t--
+	}
+	switch {
+	case t < 1.0/6:
+		return p + (q-p)*6*t
+	case t < 1.0/2:
+		return q
+	case t < 2.0/3:
+		return p + (q-p)*(2.0/3-t)*6
+	}
+	return p
+}
+
+// toByte converts a value from 0 to 1 to 0-255
+func toByte(x float64) uint8 {
+	return uint8(math.Round(max(0, min(1, x)) * 255))
+}
@@ -0,0 +1,80 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want color.NRGBA
+		ok   bool
+	}{
+		// Hex notation
+		{"hex", "#1e90ff", color.NRGBA{30, 144, 255, 255}, true},
+		{"uppercase hex", "#DC2626", color.NRGBA{220, 38, 38, 255}, true},
+		{"hex with alpha", "#ff000080", color.NRGBA{255, 0, 0, 128}, true},
+		{"issue number", "#123", color.NRGBA{}, false},
+		{"four digit number", "#1234", color.NRGBA{}, false},
+		{"hashtag", "#add", color.NRGBA{}, false},
+		{"short hex", "#fff", color.NRGBA{}, false},
+		{"short hex with alpha", "#fff8", color.NRGBA{}, false},
+		{"five digits", "#12345", color.NRGBA{}, false},
+		{"not hex", "#12345g", color.NRGBA{}, false},
+		{"missing #", "1e90ff", color.NRGBA{}, false},
+
+		// rgb()
+		{"rgb", "rgb(30, 144, 255)", color.NRGBA{30, 144, 255, 255}, true},
+		{"rgb space syntax", "rgb(30 144 255)", color.NRGBA{30, 144, 255, 255}, true},
+		{"rgba", "rgba(255, 0, 0, 0.5)", color.NRGBA{255, 0, 0, 128}, true},
+		{"rgb with slash alpha", "rgb(255 0 0 / 50%)", color.NRGBA{255, 0, 0, 128}, true},
+		{"rgb percentages", "RGB(100%, 0%, 50%)", color.NRGBA{255, 0, 128, 255}, true},
+		{"rgb out of range", "rgb(300, 0, 0)", color.NRGBA{}, false},
+		{"rgb missing component", "rgb(30, 144)", color.NRGBA{}, false},
+		{"rgb slash without alpha", "rgb(30 144 255 /)", color.NRGBA{}, false},
+
+		// hsl()
+		{"hsl", "hsl(0, 100%, 50%)", color.NRGBA{255, 0, 0, 255}, true},
+		{"hsl degrees", "hsl(210deg 100% 56%)", color.NRGBA{31, 143, 255, 255}, true},
+		{"hsl negative hue", "hsl(-120, 100%, 50%)", color.NRGBA{0, 0, 255, 255}, true},
+		{"hsl gray", "hsl(90, 0%, 50%)", color.NRGBA{128, 128, 128, 255}, true},
+		{"hsla", "hsla(120, 100%, 25%, 0.5)", color.NRGBA{0, 128, 0, 128}, true},
+		{"hsl saturation out of range", "hsl(0, 120%, 50%)", color.NRGBA{}, false},
+		{"hsl invalid hue", "hsl(red, 100%, 50%)", color.NRGBA{}, false},
+
+		// Other text
+		{"color name", "red", color.NRGBA{}, false},
+		{"function call", "rgb(x, y, z)", color.NRGBA{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseColor(tt.text)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseColor(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestClassifyColor(t *testing.T) {
+	classifier := NewClassifier(nil, 0.5)
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"#1e90ff", TypeColor},
+		{"  rgb(30, 144, 255)\n", TypeColor},
+		{"#123", TypeText},
+		{"#add", TypeText},
+	}
+	for _, tt := range tests {
+		if got := classifier.Classify([]byte(tt.text)).Type; got != tt.want {
+			t.Errorf("Classify(%q).Type = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
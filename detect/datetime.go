@@ -3,7 +3,12 @@ package detect
 // Copyright (C) 2025 Maxim Kim (exynil)
 // SPDX-License-Identifier: GPL-3.0-or-later
 
-import "regexp"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 var dateTimeRegexes = []*regexp.Regexp{
 	// ISO 8601: 2024-01-15T10:30:00Z, 2024-01-15T10:30:00+05:00
@@ -34,17 +39,26 @@ var dateTimeRegexes = []*regexp.Regexp{
 	regexp.MustCompile(`^\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}\s+\d{1,2}:\d{2}(:\d{2})?$`),
 	// DD MMM YYYY HH:MM AM/PM: 15 Jan 2024 10:30 AM
 	regexp.MustCompile(`^\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}\s+\d{1,2}:\d{2}(:\d{2})?\s+(AM|PM|am|pm)$`),
-	// Unix timestamp (10 or 13 digits)
-	regexp.MustCompile(`^\d{10}$|^\d{13}$`),
 }
 
+// Unix timestamps are only detected between these dates, so phone numbers and other ids are not
+var (
+	minUnixTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxUnixTime = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
 // IsDateTime determines if content is a date or time.
 func IsDateTime(content []byte) bool {
 	text, ok := validateAndTrim(content)
-	if !ok {
-		return false
-	}
+	return ok && isDateTime(text)
+}
 
+// isDateTime checks trimmed text
+func isDateTime(text string) bool {
+	if isDigits(text) {
+		_, ok := parseUnixTime(text)
+		return ok
+	}
 	for _, re := range dateTimeRegexes {
 		if re.MatchString(text) {
 			return true
@@ -53,3 +67,72 @@ func IsDateTime(content []byte) bool {
 
 	return false
 }
+
+// dateLayouts are the date parts of the formats matched by dateTimeRegexes.
+// Month-first layouts come first, so 01/02/2024 is January 2.
+var dateLayouts = []string{"2006-01-02", "1/2/2006", "1-2-2006", "2/1/2006", "2-1-2006", "2.1.2006", "2 Jan 2006", "2 January 2006"}
+
+// timeLayouts are the optional time parts following a date
+var timeLayouts = []string{"", " 15:04", " 15:04:05", " 3:04 PM", " 3:04:05 PM"}
+
+// ParseDateTime parses content detected by IsDateTime that includes a date.
+// Unix timestamps are returned in UTC, dates with a UTC offset in that offset
+// and dates without one in the local time zone. Times without a date are not parsed.
+func ParseDateTime(content []byte) (time.Time, bool) {
+	text, ok := validateAndTrim(content)
+	if !ok || !isDateTime(text) {
+		return time.Time{}, false
+	}
+
+	if isDigits(text) {
+		return parseUnixTime(text)
+	}
+
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, true
+	}
+
+	// time.Parse only accepts upper case AM and PM
+	text = strings.Join(strings.Fields(text), " ")
+	if n := len(text); n > 3 && (strings.HasSuffix(text, " am") || strings.HasSuffix(text, " pm")) {
+		text = text[:n-2] + strings.ToUpper(text[n-2:])
+	}
+	for _, dateLayout := range dateLayouts {
+		for _, timeLayout := range timeLayouts {
+			if t, err := time.ParseInLocation(dateLayout+timeLayout, text, time.Local); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseUnixTime parses a Unix timestamp of 10 digits in seconds or 13 digits in milliseconds.
+// Numbers with a leading zero and timestamps outside 2000-2099 are rejected.
+func parseUnixTime(text string) (time.Time, bool) {
+	if (len(text) != 10 && len(text) != 13) || text[0] == '0' {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t := time.Unix(n, 0).UTC()
+	if len(text) == 13 {
+		t = time.UnixMilli(n).UTC()
+	}
+	if t.Before(minUnixTime) || !t.Before(maxUnixTime) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// isDigits reports whether text consists of ASCII digits only
+func isDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return text != ""
+}
@@ -0,0 +1,39 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUnixTimestamp(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Time
+		ok   bool
+	}{
+		{"1700000000", time.Unix(1700000000, 0).UTC(), true},
+		{"1700000000123", time.UnixMilli(1700000000123).UTC(), true},
+		{"946684800", time.Time{}, false},  // 9 digits
+		{"0946684800", time.Time{}, false}, // leading zero
+		{"0123456789", time.Time{}, false},
+		{"0000000000000", time.Time{}, false},
+		{"946684799", time.Time{}, false},
+		{"9466847999", time.Time{}, false}, // 2269
+		{"4102444800", time.Time{}, false}, // 2100-01-01
+		{"4102444799", time.Unix(4102444799, 0).UTC(), true},
+		{"5551234567", time.Time{}, false},    // phone number
+		{"9999999999999", time.Time{}, false}, // 2286 in milliseconds
+	}
+	for _, tt := range tests {
+		got, ok := ParseDateTime([]byte(tt.text))
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("ParseDateTime(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+		if IsDateTime([]byte(tt.text)) != tt.ok {
+			t.Errorf("IsDateTime(%q) = %v, want %v", tt.text, !tt.ok, tt.ok)
+		}
+	}
+}
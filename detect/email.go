@@ -11,9 +11,10 @@ var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-
 // IsEmail determines if content is an email address.
 func IsEmail(content []byte) bool {
 	text, ok := validateAndTrim(content)
-	if !ok {
-		return false
-	}
+	return ok && isEmail(text)
+}
 
+// isEmail checks trimmed text
+func isEmail(text string) bool {
 	return emailRegex.MatchString(text)
 }
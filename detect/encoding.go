@@ -0,0 +1,151 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	xunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// Encodings reported by DecodeText
+const (
+	EncodingUTF8BOM     = "UTF-8 BOM"
+	EncodingUTF16LE     = "UTF-16LE"
+	EncodingUTF16BE     = "UTF-16BE"
+	EncodingUTF32LE     = "UTF-32LE"
+	EncodingUTF32BE     = "UTF-32BE"
+	EncodingWindows1252 = "windows-1252"
+	EncodingWindows1251 = "windows-1251"
+)
+
+// boms are the byte order marks of Unicode encodings, UTF-32 before the UTF-16 prefix it shares
+var boms = []struct {
+	mark     string
+	name     string
+	encoding encoding.Encoding
+}{
+	{"\xef\xbb\xbf", EncodingUTF8BOM, xunicode.UTF8BOM},
+	{"\xff\xfe\x00\x00", EncodingUTF32LE, utf32.UTF32(utf32.LittleEndian, utf32.ExpectBOM)},
+	{"\x00\x00\xfe\xff", EncodingUTF32BE, utf32.UTF32(utf32.BigEndian, utf32.ExpectBOM)},
+	{"\xff\xfe", EncodingUTF16LE, xunicode.UTF16(xunicode.LittleEndian, xunicode.ExpectBOM)},
+	{"\xfe\xff", EncodingUTF16BE, xunicode.UTF16(xunicode.BigEndian, xunicode.ExpectBOM)},
+}
+
+// maxHighByteShare is the largest share of bytes above 0x7F in legacy 8-bit text
+const maxHighByteShare = 0.4
+
+// DecodeText returns content as UTF-8 text. Content with a byte order mark, UTF-16 without one
+// and text in the legacy 8-bit encodings windows-1252 (Latin-1) and windows-1251 (Cyrillic) are decoded.
+// Returns the name of the decoded encoding, "" for UTF-8 content, and false for binary data.
+func DecodeText(content []byte) (string, string, bool) {
+	for _, bom := range boms {
+		if bytes.HasPrefix(content, []byte(bom.mark)) {
+			text, err := bom.encoding.NewDecoder().Bytes(content)
+			if err != nil || !isPlainText(text) {
+				return "", "", false
+			}
+			return string(text), bom.name, true
+		}
+	}
+
+	// UTF-16 of Latin text is valid UTF-8 as well, but full of zero bytes
+	if utf8.Valid(content) && bytes.IndexByte(content, 0) == -1 {
+		return string(content), "", true
+	}
+
+	if name, enc := utf16Order(content); enc != nil {
+		if text, err := enc.NewDecoder().Bytes(content); err == nil && isPlainText(text) {
+			return string(text), name, true
+		}
+		return "", "", false
+	}
+	if utf8.Valid(content) {
+		return "", "", false
+	}
+
+	name, enc := legacyEncoding(content)
+	if enc == nil {
+		return "", "", false
+	}
+	text, err := enc.NewDecoder().Bytes(content)
+	if err != nil || !isPlainText(text) {
+		return "", "", false
+	}
+	return string(text), name, true
+}
+
+// TextContent returns content decoded by DecodeText if it is in another encoding than UTF-8,
+// and content itself otherwise.
+func TextContent(content []byte) []byte {
+	if text, name, ok := DecodeText(content); ok && name != "" {
+		return []byte(text)
+	}
+	return content
+}
+
+// utf16Order guesses the byte order of UTF-16 text without a byte order mark.
+// Text in Latin scripts has a zero byte in nearly every character, on the odd bytes
+// for little endian and on the even bytes for big endian.
+func utf16Order(content []byte) (string, encoding.Encoding) {
+	if len(content) < 4 || len(content)%2 != 0 {
+		return "", nil
+	}
+	evenZeros, oddZeros := 0, 0
+	for i := 0; i < len(content); i += 2 {
+		if content[i] == 0 {
+			evenZeros++
+		}
+		if content[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	chars := len(content) / 2
+	switch {
+	case oddZeros*10 >= chars*9 && evenZeros*10 < chars:
+		return EncodingUTF16LE, xunicode.UTF16(xunicode.LittleEndian, xunicode.IgnoreBOM)
+	case evenZeros*10 >= chars*9 && oddZeros*10 < chars:
+		return EncodingUTF16BE, xunicode.UTF16(xunicode.BigEndian, xunicode.IgnoreBOM)
+	}
+	return "", nil
+}
+
+// legacyEncoding guesses the 8-bit encoding of text that is not valid UTF-8.
+// Cyrillic words are runs of bytes above 0x7F, while accented Latin letters
+// mostly stand between ASCII letters.
+func legacyEncoding(content []byte) (string, encoding.Encoding) {
+	high, inRuns := 0, 0
+	for i, b := range content {
+		if b < 0x80 {
+			continue
+		}
+		high++
+		if (i > 0 && content[i-1] >= 0x80) || (i+1 < len(content) && content[i+1] >= 0x80) {
+			inRuns++
+		}
+	}
+	if inRuns*2 > high {
+		return EncodingWindows1251, charmap.Windows1251
+	}
+	// Latin text is mostly ASCII
+	if float64(high) > float64(len(content))*maxHighByteShare {
+		return "", nil
+	}
+	return EncodingWindows1252, charmap.Windows1252
+}
+
+// isPlainText reports whether decoded text has no control characters other than whitespace
+func isPlainText(text []byte) bool {
+	for _, r := range string(text) {
+		if r == utf8.RuneError || (unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' && r != '\f') {
+			return false
+		}
+	}
+	return true
+}
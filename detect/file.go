@@ -0,0 +1,42 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FileListType is the MIME type of files copied in a file manager
+const FileListType = "text/uri-list"
+
+// ParseFileList parses a text/uri-list of file:// URIs, as copied by file managers, into local paths.
+// Comment lines starting with # are skipped. Returns false if any other line is not a local file URI.
+func ParseFileList(content []byte) ([]string, bool) {
+	text, ok := validateAndTrim(content)
+	if !ok {
+		return nil, false
+	}
+	return parseFileList(text)
+}
+
+// parseFileList parses trimmed text
+func parseFileList(text string) ([]string, bool) {
+	var paths []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(strings.ToLower(line), "file://") {
+			return nil, false
+		}
+		u, err := url.Parse(line)
+		if err != nil || (u.Host != "" && u.Host != "localhost") || u.Path == "" {
+			return nil, false
+		}
+		paths = append(paths, u.Path)
+	}
+	return paths, len(paths) > 0
+}
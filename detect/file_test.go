@@ -0,0 +1,39 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFileList(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string // nil if text is no file list
+	}{
+		{"single file", "file:///home/user/notes.txt", []string{"/home/user/notes.txt"}},
+		{"several files", "file:///home/user/a.txt\r\nfile:///home/user/b.png\r\n", []string{"/home/user/a.txt", "/home/user/b.png"}},
+		{"escaped characters", "file:///home/user/My%20Documents/r%C3%A9sum%C3%A9.pdf", []string{"/home/user/My Documents/résumé.pdf"}},
+		{"localhost", "file://localhost/etc/hosts", []string{"/etc/hosts"}},
+		{"uppercase scheme", "FILE:///tmp/x", []string{"/tmp/x"}},
+		{"comment lines", "# copied by a file manager\nfile:///tmp/x", []string{"/tmp/x"}},
+		{"directory", "file:///home/user/", []string{"/home/user/"}},
+		{"remote host", "file://server/share/x", nil},
+		{"http url", "https://example.com/x", nil},
+		{"mixed with text", "file:///tmp/x\nand some text", nil},
+		{"only comments", "# nothing here", nil},
+		{"no path", "file://", nil},
+		{"plain path", "/home/user/notes.txt", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseFileList([]byte(tt.text))
+			if ok != (tt.want != nil) || !slices.Equal(got, tt.want) {
+				t.Errorf("ParseFileList(%q) = %q, %v, want %q", tt.text, got, ok, tt.want)
+			}
+		})
+	}
+}
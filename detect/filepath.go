@@ -14,10 +14,11 @@ const minPathComponents = 2
 // IsFilePath determines if content is a file path.
 func IsFilePath(content []byte) bool {
 	text, ok := validateAndTrim(content)
-	if !ok {
-		return false
-	}
+	return ok && isFilePath(text)
+}
 
+// isFilePath checks trimmed text
+func isFilePath(text string) bool {
 	// Normalize the path
 	cleaned := filepath.Clean(text)
 	if cleaned == "." || cleaned == ".." {
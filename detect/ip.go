@@ -8,9 +8,10 @@ import "net"
 // IsIP determines if content is an IP address (IPv4 or IPv6).
 func IsIP(content []byte) bool {
 	text, ok := validateAndTrim(content)
-	if !ok {
-		return false
-	}
+	return ok && isIP(text)
+}
 
+// isIP checks trimmed text
+func isIP(text string) bool {
 	return net.ParseIP(text) != nil
 }
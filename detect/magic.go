@@ -0,0 +1,184 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// BinaryFormat is a file format identified by its magic number
+type BinaryFormat struct {
+	Name      string // Short name shown in previews, such as "PDF" or "DOCX"
+	Extension string // File extension without the dot, empty for executables
+	MimeType  string
+}
+
+// signature is a magic number at a fixed offset
+type signature struct {
+	offset int
+	magic  string
+	format BinaryFormat
+}
+
+// signatures are the magic numbers of common formats, checked in order
+var signatures = []signature{
+	{0, "%PDF-", BinaryFormat{"PDF", "pdf", "application/pdf"}},
+	{0, "\x1f\x8b", BinaryFormat{"GZIP", "gz", "application/gzip"}},
+	{0, "BZh", BinaryFormat{"BZIP2", "bz2", "application/x-bzip2"}},
+	{0, "\xfd7zXZ\x00", BinaryFormat{"XZ", "xz", "application/x-xz"}},
+	{0, "\x28\xb5\x2f\xfd", BinaryFormat{"ZSTD", "zst", "application/zstd"}},
+	{0, "7z\xbc\xaf\x27\x1c", BinaryFormat{"7Z", "7z", "application/x-7z-compressed"}},
+	{0, "Rar!\x1a\x07", BinaryFormat{"RAR", "rar", "application/vnd.rar"}},
+	{257, "ustar", BinaryFormat{"TAR", "tar", "application/x-tar"}},
+	{0, "\x7fELF", BinaryFormat{"ELF", "", "application/x-executable"}},
+	{0, "\x00asm", BinaryFormat{"WASM", "wasm", "application/wasm"}},
+	{0, "SQLite format 3\x00", BinaryFormat{"SQLite", "sqlite", "application/vnd.sqlite3"}},
+	{0, "\x00\x01\x00\x00\x00", BinaryFormat{"TTF", "ttf", "font/ttf"}},
+	{0, "OTTO", BinaryFormat{"OTF", "otf", "font/otf"}},
+	{0, "ttcf", BinaryFormat{"TTC", "ttc", "font/collection"}},
+	{0, "wOFF", BinaryFormat{"WOFF", "woff", "font/woff"}},
+	{0, "wOF2", BinaryFormat{"WOFF2", "woff2", "font/woff2"}},
+	{0, "ID3", BinaryFormat{"MP3", "mp3", "audio/mpeg"}},
+	{0, "\xff\xfb", BinaryFormat{"MP3", "mp3", "audio/mpeg"}},
+	{0, "\xff\xf3", BinaryFormat{"MP3", "mp3", "audio/mpeg"}},
+	{0, "\xff\xf2", BinaryFormat{"MP3", "mp3", "audio/mpeg"}},
+	{0, "fLaC", BinaryFormat{"FLAC", "flac", "audio/flac"}},
+	{0, "OggS", BinaryFormat{"OGG", "ogg", "audio/ogg"}},
+	{0, "MThd", BinaryFormat{"MIDI", "mid", "audio/midi"}},
+	{0, "II*\x00", BinaryFormat{"TIFF", "tiff", "image/tiff"}},
+	{0, "MM\x00*", BinaryFormat{"TIFF", "tiff", "image/tiff"}},
+	{0, "\x00\x00\x01\x00", BinaryFormat{"ICO", "ico", "image/vnd.microsoft.icon"}},
+	{0, "8BPS", BinaryFormat{"PSD", "psd", "image/vnd.adobe.photoshop"}},
+}
+
+// ftypBrands are the formats of ISO base media files (MP4 and relatives) by their major brand
+var ftypBrands = map[string]BinaryFormat{
+	"qt  ": {"MOV", "mov", "video/quicktime"},
+	"M4A ": {"M4A", "m4a", "audio/mp4"},
+	"M4B ": {"M4A", "m4a", "audio/mp4"},
+	"heic": {"HEIC", "heic", "image/heic"},
+	"heix": {"HEIC", "heic", "image/heic"},
+	"mif1": {"HEIF", "heif", "image/heif"},
+	"avif": {"AVIF", "avif", "image/avif"},
+	"3gp4": {"3GP", "3gp", "video/3gpp"},
+	"3gp5": {"3GP", "3gp", "video/3gpp"},
+}
+
+// riffFormats are the formats of RIFF containers by their form type
+var riffFormats = map[string]BinaryFormat{
+	"WAVE": {"WAV", "wav", "audio/wav"},
+	"AVI ": {"AVI", "avi", "video/x-msvideo"},
+	"WEBP": {"WEBP", "webp", "image/webp"},
+}
+
+// zipFormats are the formats built on ZIP by a file they contain, checked in order
+var zipFormats = []struct {
+	file   string
+	format BinaryFormat
+}{
+	{"word/document.xml", BinaryFormat{"DOCX", "docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"}},
+	{"xl/workbook.xml", BinaryFormat{"XLSX", "xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}},
+	{"ppt/presentation.xml", BinaryFormat{"PPTX", "pptx", "application/vnd.openxmlformats-officedocument.presentationml.presentation"}},
+	{"AndroidManifest.xml", BinaryFormat{"APK", "apk", "application/vnd.android.package-archive"}},
+	{"META-INF/MANIFEST.MF", BinaryFormat{"JAR", "jar", "application/java-archive"}},
+}
+
+// zipMimeFormats are the formats built on ZIP that name their mime type in a "mimetype" file
+var zipMimeFormats = map[string]BinaryFormat{
+	"application/epub+zip":                            {"EPUB", "epub", "application/epub+zip"},
+	"application/vnd.oasis.opendocument.text":         {"ODT", "odt", "application/vnd.oasis.opendocument.text"},
+	"application/vnd.oasis.opendocument.spreadsheet":  {"ODS", "ods", "application/vnd.oasis.opendocument.spreadsheet"},
+	"application/vnd.oasis.opendocument.presentation": {"ODP", "odp", "application/vnd.oasis.opendocument.presentation"},
+}
+
+var (
+	zipFormat      = BinaryFormat{"ZIP", "zip", "application/zip"}
+	mp4Format      = BinaryFormat{"MP4", "mp4", "video/mp4"}
+	matroskaFormat = BinaryFormat{"MKV", "mkv", "video/x-matroska"}
+	webmFormat     = BinaryFormat{"WEBM", "webm", "video/webm"}
+	bmpFormat      = BinaryFormat{"BMP", "bmp", "image/bmp"}
+	exeFormat      = BinaryFormat{"EXE", "exe", "application/vnd.microsoft.portable-executable"}
+)
+
+// DetectBinaryFormat identifies the file format of content by its magic number.
+// Covers documents, archives, executables, databases, fonts, audio and video,
+// and the image formats image.DetectImageFormat can't decode.
+func DetectBinaryFormat(content []byte) (BinaryFormat, bool) {
+	switch {
+	case bytes.HasPrefix(content, []byte("PK\x03\x04")) || bytes.HasPrefix(content, []byte("PK\x05\x06")):
+		return detectZipFormat(content), true
+	case len(content) >= 12 && string(content[4:8]) == "ftyp":
+		if format, ok := ftypBrands[string(content[8:12])]; ok {
+			return format, true
+		}
+		return mp4Format, true
+	case len(content) >= 12 && bytes.HasPrefix(content, []byte("RIFF")):
+		format, ok := riffFormats[string(content[8:12])]
+		return format, ok
+	case bytes.HasPrefix(content, []byte("\x1a\x45\xdf\xa3")):
+		// The doctype of a Matroska file is in its header
+		if bytes.Contains(content[:min(len(content), 64)], []byte("webm")) {
+			return webmFormat, true
+		}
+		return matroskaFormat, true
+	case isBMP(content):
+		return bmpFormat, true
+	case isPE(content):
+		return exeFormat, true
+	}
+
+	for _, s := range signatures {
+		if len(content) >= s.offset+len(s.magic) && string(content[s.offset:s.offset+len(s.magic)]) == s.magic {
+			return s.format, true
+		}
+	}
+	return BinaryFormat{}, false
+}
+
+// detectZipFormat identifies the format of a ZIP archive by the files it contains
+func detectZipFormat(content []byte) BinaryFormat {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return zipFormat
+	}
+	names := make(map[string]*zip.File, len(reader.File))
+	for _, f := range reader.File {
+		names[f.Name] = f
+	}
+	if f, ok := names["mimetype"]; ok {
+		if rc, err := f.Open(); err == nil {
+			mimeType, _ := io.ReadAll(io.LimitReader(rc, 128))
+			rc.Close()
+			if format, ok := zipMimeFormats[string(bytes.TrimSpace(mimeType))]; ok {
+				return format
+			}
+		}
+	}
+	for _, z := range zipFormats {
+		if _, ok := names[z.file]; ok {
+			return z.format
+		}
+	}
+	return zipFormat
+}
+
+// isBMP checks the signature and the file size in the header of a BMP image
+func isBMP(content []byte) bool {
+	if len(content) < 26 || !bytes.HasPrefix(content, []byte("BM")) {
+		return false
+	}
+	return int(binary.LittleEndian.Uint32(content[2:6])) == len(content)
+}
+
+// isPE checks the DOS header and the PE signature it points to of a Windows executable
+func isPE(content []byte) bool {
+	if len(content) < 64 || !bytes.HasPrefix(content, []byte("MZ")) {
+		return false
+	}
+	offset := int(binary.LittleEndian.Uint32(content[60:64]))
+	return offset+4 <= len(content) && string(content[offset:offset+4]) == "PE\x00\x00"
+}
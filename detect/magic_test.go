@@ -0,0 +1,141 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// zipWith returns a ZIP archive containing the named files, each with its content
+func zipWith(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for i := 0; i+1 < len(files); i += 2 {
+		f, err := w.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// tarWith returns a tar archive containing one file
+func tarWith(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// ftyp returns the start of an ISO base media file with the given major brand
+func ftyp(brand string) []byte {
+	return []byte("\x00\x00\x00\x18ftyp" + brand + "\x00\x00\x00\x00isom")
+}
+
+// bmp returns a BMP header whose file size field is size
+func bmp(size uint32, length int) []byte {
+	data := make([]byte, length)
+	copy(data, "BM")
+	binary.LittleEndian.PutUint32(data[2:6], size)
+	return data
+}
+
+// pe returns a DOS header pointing to a PE signature at offset
+func pe(offset uint32, signature string) []byte {
+	data := make([]byte, 128)
+	copy(data, "MZ")
+	binary.LittleEndian.PutUint32(data[60:64], offset)
+	copy(data[offset:], signature)
+	return data
+}
+
+func TestDetectBinaryFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string // Name of the format, empty if none is detected
+	}{
+		// Fixed signatures
+		{"pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3"), "PDF"},
+		{"gzip", []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00"), "GZIP"},
+		{"elf", []byte("\x7fELF\x02\x01\x01\x00"), "ELF"},
+		{"sqlite", []byte("SQLite format 3\x00\x10\x00"), "SQLite"},
+		{"tiff", []byte("II*\x00\x08\x00\x00\x00"), "TIFF"},
+
+		// tar has its signature at offset 257
+		{"tar", tarWith(t, "notes.txt", "hello"), "TAR"},
+		{"ustar shorter than its offset", []byte("ustar"), ""},
+		{"ustar at the wrong offset", append(make([]byte, 256), "ustar\x00"...), ""},
+
+		// ISO base media files by their major brand
+		{"mp4", ftyp("isom"), "MP4"},
+		{"unknown brand", ftyp("abcd"), "MP4"},
+		{"quicktime", ftyp("qt  "), "MOV"},
+		{"m4a", ftyp("M4A "), "M4A"},
+		{"heic", ftyp("heic"), "HEIC"},
+		{"avif", ftyp("avif"), "AVIF"},
+		{"ftyp without brand", []byte("\x00\x00\x00\x18ftyp"), ""},
+
+		// RIFF containers by their form type
+		{"webp", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), "WEBP"},
+		{"wav", []byte("RIFF\x24\x00\x00\x00WAVEfmt "), "WAV"},
+		{"unknown riff", []byte("RIFF\x24\x00\x00\x00ABCD"), ""},
+
+		// ZIP based formats by the files they contain
+		{"zip", zipWith(t, "notes.txt", "hello"), "ZIP"},
+		{"docx", zipWith(t, "[Content_Types].xml", "", "word/document.xml", ""), "DOCX"},
+		{"xlsx", zipWith(t, "[Content_Types].xml", "", "xl/workbook.xml", ""), "XLSX"},
+		{"pptx", zipWith(t, "ppt/presentation.xml", ""), "PPTX"},
+		{"jar", zipWith(t, "META-INF/MANIFEST.MF", "Manifest-Version: 1.0"), "JAR"},
+		{"apk before jar", zipWith(t, "META-INF/MANIFEST.MF", "", "AndroidManifest.xml", ""), "APK"},
+		{"epub", zipWith(t, "mimetype", "application/epub+zip", "META-INF/container.xml", ""), "EPUB"},
+		{"odt", zipWith(t, "mimetype", "application/vnd.oasis.opendocument.text"), "ODT"},
+		{"unknown mimetype", zipWith(t, "mimetype", "application/x-unknown", "word/document.xml", ""), "DOCX"},
+		{"truncated zip", []byte("PK\x03\x04\x14\x00\x00\x00"), "ZIP"},
+		{"empty zip", []byte("PK\x05\x06" + string(make([]byte, 18))), "ZIP"},
+
+		// Matroska by its doctype
+		{"mkv", []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x88matroska"), "MKV"},
+		{"webm", []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01\x42\x82\x84webm"), "WEBM"},
+
+		// Headers checked beyond their signature
+		{"bmp", bmp(64, 64), "BMP"},
+		{"bmp with wrong size", bmp(1000, 64), ""},
+		{"exe", pe(64, "PE\x00\x00"), "EXE"},
+		{"dos header without pe", pe(64, "NE"), ""},
+		{"pe offset past the end", pe(100, "PE\x00\x00")[:64], ""},
+
+		// Other content
+		{"text", []byte("hello world"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, ok := DetectBinaryFormat(tt.data)
+			if format.Name != tt.want || ok != (tt.want != "") {
+				t.Errorf("DetectBinaryFormat = %+v, %v, want %q", format, ok, tt.want)
+			}
+		})
+	}
+}
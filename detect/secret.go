@@ -0,0 +1,285 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	minSecretLength = 8
+	maxSecretLength = 128
+)
+
+// SecretPassword is the kind of secrets recognized by their entropy instead of a token format
+const SecretPassword = "password"
+
+// Secret is the result of ClassifySecret
+type Secret struct {
+	Kind       string  // Token format such as "github-token", SecretPassword, or empty if content is no secret
+	Confidence float64 // From 0 (certainly not a secret) to 1
+}
+
+// tokenSignature is a well-known token format
+type tokenSignature struct {
+	kind       string
+	confidence float64
+	re         *regexp.Regexp
+}
+
+// tokenSignatures are checked in order, more specific prefixes first
+var tokenSignatures = []tokenSignature{
+	{"github-token", 0.99, regexp.MustCompile(`^(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}$`)},
+	{"github-token", 0.99, regexp.MustCompile(`^github_pat_[A-Za-z0-9_]{22,}$`)},
+	{"gitlab-token", 0.99, regexp.MustCompile(`^glpat-[A-Za-z0-9_\-]{20,}$`)},
+	{"anthropic-key", 0.99, regexp.MustCompile(`^sk-ant-[A-Za-z0-9_\-]{20,}$`)},
+	{"stripe-key", 0.99, regexp.MustCompile(`^(sk|rk)_(live|test)_[A-Za-z0-9]{16,}$`)},
+	{"openai-key", 0.95, regexp.MustCompile(`^sk-[A-Za-z0-9_\-]{20,}$`)},
+	{"aws-access-key", 0.95, regexp.MustCompile(`^(AKIA|ASIA)[A-Z0-9]{16}$`)},
+	{"google-api-key", 0.95, regexp.MustCompile(`^AIza[A-Za-z0-9_\-]{35}$`)},
+	{"slack-token", 0.99, regexp.MustCompile(`^xox[abposr]-[A-Za-z0-9\-]{10,}$`)},
+	{"npm-token", 0.99, regexp.MustCompile(`^npm_[A-Za-z0-9]{36}$`)},
+	{"jwt", 0.95, regexp.MustCompile(`^eyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*$`)},
+}
+
+// ClassifySecret estimates whether content is a password, API key or access token.
+// Known token formats are recognized by their signature, other single words are
+// scored by their Shannon entropy and how randomly character classes are mixed.
+// Content matching one of ignorePatterns is never a secret.
+func ClassifySecret(content []byte, ignorePatterns []string) Secret {
+	text, ok := validateAndTrim(content)
+	if !ok {
+		return Secret{}
+	}
+	return classifySecret(text, compilePatterns(ignorePatterns))
+}
+
+// IsSecret reports whether content is a secret with at least the given confidence.
+func IsSecret(content []byte, ignorePatterns []string, threshold float64) bool {
+	secret := ClassifySecret(content, ignorePatterns)
+	return secret.Kind != "" && secret.Confidence >= threshold
+}
+
+// classifySecret checks trimmed text
+func classifySecret(text string, ignorePatterns []*regexp.Regexp) Secret {
+	// Check user-defined ignore patterns
+	for _, re := range ignorePatterns {
+		if re.MatchString(text) {
+			return Secret{}
+		}
+	}
+
+	for _, sig := range tokenSignatures {
+		if sig.re.MatchString(text) {
+			return Secret{Kind: sig.kind, Confidence: sig.confidence}
+		}
+	}
+
+	if !isSecretCandidate(text) {
+		return Secret{}
+	}
+
+	confidence := max(entropyScore(text), weakPasswordScore(text))
+	if confidence == 0 {
+		return Secret{}
+	}
+	return Secret{Kind: SecretPassword, Confidence: confidence}
+}
+
+var (
+	// versionRegex matches version numbers such as 1.2, v1.2.3 or 2.0.0-rc1+build.5
+	versionRegex = regexp.MustCompile(`^[vV]?\d+(\.\d+)+([-+][0-9A-Za-z.\-+]+)?$`)
+	// callRegex matches function calls such as rgb(300,0,0) or len(x)
+	callRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*\(.*\)$`)
+	// wordDigitsRegex matches a word followed by a few digits, the most common password shape, such as Qwerty123 or hunter22
+	wordDigitsRegex = regexp.MustCompile(`^[A-Za-z]{4,}[0-9]{1,4}[[:punct:]]?$`)
+)
+
+// weakPasswordConfidence is the confidence of common password shapes that score low on entropy
+const weakPasswordConfidence = 0.6
+
+// isSecretCandidate reports whether text is a single printable word that is none of the other detected types.
+func isSecretCandidate(text string) bool {
+	length := len([]rune(text))
+	if length < minSecretLength || length > maxSecretLength {
+		return false
+	}
+
+	// Check if it's a date/time (before space check, as dates can contain spaces)
+	if isDateTime(text) {
+		return false
+	}
+
+	for _, r := range text {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	if versionRegex.MatchString(text) || callRegex.MatchString(text) {
+		return false
+	}
+
+	return !isEmail(text) && !isURL(text) && !isIP(text) && !isUUID(text) && !isFilePath(text)
+}
+
+// weakPasswordScore returns weakPasswordConfidence for words shaped like a human-chosen password:
+// a word followed by digits, or a mix of at least three character classes that is no identifier.
+func weakPasswordScore(text string) float64 {
+	if wordDigitsRegex.MatchString(text) {
+		return weakPasswordConfidence
+	}
+	runes := []rune(text)
+	if countClasses(runes) >= 3 && !looksLikeIdentifier(runes) {
+		return weakPasswordConfidence
+	}
+	return 0
+}
+
+// countClasses returns the number of character classes in runes
+func countClasses(runes []rune) int {
+	var classes [4]bool
+	for _, r := range runes {
+		classes[charClass(r)] = true
+	}
+	count := 0
+	for _, present := range classes {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// charClass of a rune: lowercase, uppercase, digit or other
+func charClass(r rune) int {
+	switch {
+	case unicode.IsLower(r):
+		return 0
+	case unicode.IsUpper(r):
+		return 1
+	case unicode.IsDigit(r):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// entropyScore scores a word from 0 to 1. Half of the score is the entropy per character,
+// the rest is the number of character classes and how often adjacent characters change class.
+// Identifiers such as getUserName or com.example.Main and hex strings such as commit hashes score lower.
+func entropyScore(text string) float64 {
+	runes := []rune(text)
+
+	transitions := 0
+	for i, r := range runes {
+		if i > 0 && charClass(r) != charClass(runes[i-1]) {
+			transitions++
+		}
+	}
+	classCount := countClasses(runes)
+	if classCount < 2 {
+		return 0
+	}
+
+	entropyPart := clamp((shannonEntropy(runes) - 2.5) / 1.5)
+	classPart := float64(classCount-1) / 3
+	transitionPart := clamp(float64(transitions) / float64(len(runes)-1) / 0.5)
+	score := entropyPart*0.5 + classPart*0.25 + transitionPart*0.25
+
+	if isHex(text) {
+		score *= 0.5
+	}
+	if looksLikeIdentifier(runes) {
+		score *= 0.3
+	}
+	return score
+}
+
+// shannonEntropy returns the entropy in bits per character
+func shannonEntropy(runes []rune) float64 {
+	counts := map[rune]int{}
+	for _, r := range runes {
+		counts[r]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(len(runes))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func clamp(x float64) float64 {
+	return max(0, min(1, x))
+}
+
+// isHex reports whether text consists of hexadecimal digits only
+func isHex(text string) bool {
+	for _, r := range text {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// looksLikeIdentifier reports whether runes are words joined by camel case or the separators . _ - / :
+// and numbers, like MyClassName2, hello_world_2024, XMLHttpRequest or v1.2.3.
+func looksLikeIdentifier(runes []rune) bool {
+	var words [][]rune
+	start := 0
+	split := func(end int) {
+		if end > start {
+			words = append(words, runes[start:end])
+		}
+		start = end
+	}
+
+	for i, r := range runes {
+		if strings.ContainsRune("._-/:", r) {
+			split(i)
+			start = i + 1
+			continue
+		}
+		if charClass(r) == 3 {
+			return false
+		}
+		if i == start {
+			continue
+		}
+		previous := charClass(runes[i-1])
+		switch class := charClass(r); {
+		case class == 1 && previous == 0:
+			// camelCase
+			split(i)
+		case class == 0 && previous == 1 && i-1 > start && charClass(runes[i-2]) == 1:
+			// XMLHttp: the last capital starts the next word
+			split(i - 1)
+		case class != previous && !(class == 0 && previous == 1):
+			split(i)
+		}
+	}
+	split(len(runes))
+
+	for i, word := range words {
+		switch {
+		case charClass(word[0]) == 2:
+		case len(word) == 1:
+			// Single letters start a word (iPhone) or a number (v1, x86)
+			if i > 0 && (i+1 == len(words) || charClass(words[i+1][0]) != 2) {
+				return false
+			}
+		case len(word) < 4 && strings.IndexFunc(string(word), unicode.IsLower) >= 0:
+			// Short lowercase words need a vowel, unlike random pairs such as Zk
+			if !strings.ContainsAny(strings.ToLower(string(word)), "aeiouy") {
+				return false
+			}
+		}
+	}
+	return len(words) > 0
+}
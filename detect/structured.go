@@ -0,0 +1,180 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IsJSON checks if content is a JSON object or array.
+// Bare scalars such as numbers are valid JSON but not detected.
+func IsJSON(content []byte) bool {
+	text, ok := validateAndTrim(content)
+	return ok && isJSON(text)
+}
+
+// isJSON checks trimmed text
+func isJSON(text string) bool {
+	if !strings.HasPrefix(text, "{") && !strings.HasPrefix(text, "[") {
+		return false
+	}
+	return json.Valid([]byte(text))
+}
+
+// IsYAML checks if content is a YAML document with a block mapping or list,
+// such as a config file. Single key: value lines and plain lists of words are not detected,
+// and neither are "Key: value" lines of prose, mail headers or a heading over a list
+// without other evidence of YAML.
+func IsYAML(content []byte) bool {
+	text, ok := validateAndTrim(content)
+	return ok && isYAML(text)
+}
+
+// isYAML checks trimmed text
+func isYAML(text string) bool {
+	if !strings.ContainsAny(text, "\r\n") || strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+		return false
+	}
+	root, err := ParseYAML([]byte(text))
+	if err != nil || root == nil {
+		return false
+	}
+	switch root.Kind {
+	case yaml.MappingNode:
+		// A single heading over a list, as in "Meeting notes:" followed by "- buy milk", is prose
+		if hasCollection(root) {
+			return hasPlainKeys(root) || len(root.Content) >= 4
+		}
+		// Flat mappings need a document marker or keys like config keys
+		return len(root.Content) >= 4 && (strings.HasPrefix(text, "---") || hasPlainKeys(root))
+	case yaml.SequenceNode:
+		return hasCollection(root)
+	}
+	return false
+}
+
+// hasCollection reports whether a YAML node contains a mapping or list
+func hasCollection(node *yaml.Node) bool {
+	for _, child := range node.Content {
+		if child.Kind == yaml.MappingNode || child.Kind == yaml.SequenceNode {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlKeyRegex matches keys as used in config files, without spaces or capitals
+var yamlKeyRegex = regexp.MustCompile(`^[a-z0-9_.\-]+$`)
+
+// hasPlainKeys reports whether all keys of a YAML mapping look like config keys,
+// unlike "Subject: lunch" or "Note: remember"
+func hasPlainKeys(mapping *yaml.Node) bool {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if !yamlKeyRegex.MatchString(mapping.Content[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseYAML parses the first document of YAML content.
+// Returns the root node of the document, or nil if it is empty.
+func ParseYAML(content []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// htmlRegex matches the doctype or common elements of an HTML document or fragment
+var htmlRegex = regexp.MustCompile(`(?i)^<!doctype\s+html|<(html|head|body|div|span|p|a|ul|ol|li|table|tr|td|form|input|script|style|img|br|h[1-6])[\s/>]`)
+
+// htmlRootRegex matches the root element names of HTML documents and fragments
+var htmlRootRegex = regexp.MustCompile(`(?i)^(html|head|body|div|span|p|a|ul|ol|li|table|tr|td|form|input|script|style|img|br|h[1-6]|section|article|nav|header|footer|main|button|select|label|pre|code|svg)$`)
+
+// markupType returns TypeXML or TypeHTML for text that is a markup document, or "" otherwise.
+// HTML is recognized by its elements, any other markup must be well-formed XML with a single root element.
+func markupType(text string) string {
+	if !strings.HasPrefix(text, "<") || !strings.HasSuffix(text, ">") || strings.HasPrefix(text, "<?php") {
+		return ""
+	}
+	root, _, err := ParseXML([]byte(text))
+	switch {
+	case err == nil && htmlRootRegex.MatchString(root):
+		return TypeHTML
+	case err == nil:
+		return TypeXML
+	case htmlRegex.MatchString(text):
+		return TypeHTML
+	}
+	return ""
+}
+
+// ParseXML checks that content is well-formed XML with a single root element.
+// Returns the name of the root and the number of elements.
+func ParseXML(content []byte) (string, int, error) {
+	decoder := xml.NewDecoder(strings.NewReader(string(content)))
+	root := ""
+	depth, elements := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 && root != "" {
+				return "", 0, errors.New("more than one root element")
+			}
+			if depth == 0 {
+				root = t.Name.Local
+			}
+			depth++
+			elements++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && strings.TrimSpace(string(t)) != "" {
+				return "", 0, errors.New("text outside the root element")
+			}
+		}
+	}
+	if root == "" {
+		return "", 0, errors.New("no root element")
+	}
+	return root, elements, nil
+}
+
+// sqlRegex matches the start of common SQL statements
+var sqlRegex = regexp.MustCompile(`(?is)^(SELECT\s.+?\sFROM\s|INSERT\s+INTO\s|UPDATE\s+\S+\s+SET\s|DELETE\s+FROM\s|` +
+	`CREATE\s+(OR\s+REPLACE\s+)?(TABLE|(UNIQUE\s+)?INDEX|VIEW|DATABASE|SCHEMA|FUNCTION|TRIGGER)\s|` +
+	`ALTER\s+TABLE\s|DROP\s+(TABLE|INDEX|VIEW|DATABASE|SCHEMA)\s|WITH\s+\w+\s+AS\s*\()`)
+
+// sqlCommentRegex matches leading comment lines of an SQL script
+var sqlCommentRegex = regexp.MustCompile(`^(\s*--[^\n]*\n)+`)
+
+// isSQL checks if trimmed text is an SQL statement.
+// Lowercase statements must end with a semicolon, so sentences like "select the file from the menu" don't match.
+func isSQL(text string) bool {
+	text = sqlCommentRegex.ReplaceAllString(text, "")
+	if !sqlRegex.MatchString(text) {
+		return false
+	}
+	keyword := strings.Fields(text)[0]
+	return keyword == strings.ToUpper(keyword) || strings.HasSuffix(text, ";")
+}
@@ -0,0 +1,40 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import "testing"
+
+func TestIsYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"config keys", "name: clipbox\nversion: 1.2\n", true},
+		{"document marker", "---\nTitle: Notes\nAuthor: Bob\n", true},
+		{"nested mapping", "server:\n  Host: localhost\n  Port: 8080\n", true},
+		{"list value", "fruits:\n  - apple\n  - pear\n", true},
+		{"lists under headings", "Fruits:\n  - apple\n  - pear\nVegetables:\n  - leek\n", true},
+		{"kubernetes manifest", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n", true},
+		{"list of mappings", "- name: a\n- name: b\n", true},
+		{"prose keys", "Note: remember\nTodo: buy milk", false},
+		{"mail headers", "To: bob\nSubject: lunch", false},
+		{"heading over a list", "Meeting notes:\n- buy milk\n- call mom", false},
+		{"capitalized heading over a list", "Shopping:\n  - milk\n  - eggs\n", false},
+		{"heading over a mapping", "Contact:\n  Name: Bob\n  Phone: 555-1234\n", false},
+		{"keys with spaces", "first name: Bob\nlast name: Smith", false},
+		{"single pair", "name: clipbox\n", false},
+		{"plain list", "- milk\n- eggs\n", false},
+		{"single line", "name: clipbox", false},
+		{"json", "{\"a\": 1,\n\"b\": 2}", false},
+		{"text", "hello\nworld", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsYAML([]byte(tt.text)); got != tt.want {
+				t.Errorf("IsYAML(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
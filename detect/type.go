@@ -0,0 +1,27 @@
+package detect
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Content types returned by Classify
+const (
+	TypeImage      = "image"
+	TypeBinary     = "binary"
+	TypeURL        = "url"
+	TypeEmail      = "email"
+	TypeIP         = "ip"
+	TypeUUID       = "uuid"
+	TypeDateTime   = "datetime"
+	TypePath       = "path"
+	TypeFile       = "file"
+	TypeColor      = "color"
+	TypeJSON       = "json"
+	TypeYAML       = "yaml"
+	TypeXML        = "xml"
+	TypeHTML       = "html"
+	TypeSQL        = "sql"
+	TypeCode       = "code"
+	TypePassword   = "password"
+	TypeCredential = "credential"
+	TypeText       = "text"
+)
@@ -20,10 +20,11 @@ var urlSchemes = []string{
 // IsURL determines if content is a URL.
 func IsURL(content []byte) bool {
 	text, ok := validateAndTrim(content)
-	if !ok {
-		return false
-	}
+	return ok && isURL(text)
+}
 
+// isURL checks trimmed text
+func isURL(text string) bool {
 	textLower := strings.ToLower(text)
 	for _, scheme := range urlSchemes {
 		if strings.HasPrefix(textLower, scheme) {
@@ -10,9 +10,10 @@ var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4
 // IsUUID determines if content is a UUID.
 func IsUUID(content []byte) bool {
 	text, ok := validateAndTrim(content)
-	if !ok {
-		return false
-	}
+	return ok && isUUID(text)
+}
 
+// isUUID checks trimmed text
+func isUUID(text string) bool {
 	return uuidRegex.MatchString(text)
 }
@@ -0,0 +1,151 @@
+package export
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/detect"
+	"clipbox/image"
+	"clipbox/preview"
+	"clipbox/utils"
+)
+
+// SchemaVersion is the version of the JSON and TSV output.
+// Fields may be added within a version, but are never renamed, removed or reordered in TSV.
+// Type is an open set: new types may be added within a version, so consumers should treat
+// unknown types like "text". The version changes when existing content gets another type.
+// Version 2 reports json, yaml, xml, html, sql, code, credential, file and color, which were text in version 1.
+const SchemaVersion = 2
+
+// Item is a clipboard entry in the machine-readable output
+type Item struct {
+	ID        int       `json:"id"`
+	Buffer    int       `json:"buffer"`
+	Pinned    bool      `json:"pinned"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int       `json:"size"`
+	Type      string    `json:"type"` // One of the detect.Type constants
+	Source    string    `json:"source"`
+	SourceApp string    `json:"source_app"`
+	Preview   string    `json:"preview"` // Plain text preview without markers
+	Icon      string    `json:"icon"`    // Path to the icon file, empty if there is none
+}
+
+// ListDocument is the output of --list --json
+type ListDocument struct {
+	Version    int    `json:"version"`
+	Buffer     int    `json:"buffer"`
+	BufferName string `json:"buffer_name"`
+	Entries    []Item `json:"entries"`
+}
+
+// GetDocument is the output of --get ID --json.
+// Content holds text entries, ContentBase64 holds binary entries.
+type GetDocument struct {
+	Version int `json:"version"`
+	Entry   struct {
+		Item
+		Content       *string `json:"content,omitempty"`
+		ContentBase64 []byte  `json:"content_base64,omitempty"`
+	} `json:"entry"`
+}
+
+// NewItem builds an output item from a stored record and its plain text preview.
+func NewItem(record *database.Record, previewText string, cfg *config.Config) Item {
+	icon, _ := image.GetIconPath(record.ID)
+	return Item{
+		ID:        record.ID,
+		Buffer:    record.Buffer,
+		Pinned:    record.Pinned,
+		CreatedAt: record.CreatedAt.UTC(),
+		Size:      len(record.Content),
+		Type:      contentType(record, cfg),
+		Source:    record.Source,
+		SourceApp: record.SourceApp,
+		Preview:   previewText,
+		Icon:      icon,
+	}
+}
+
+// contentType returns the stored type of a record, or classifies it if the previews were not rebuilt yet
+func contentType(record *database.Record, cfg *config.Config) string {
+	if record.ContentType != "" {
+		return record.ContentType
+	}
+	bufferCfg := cfg.ForBuffer(record.Buffer)
+	return detect.NewClassifier(bufferCfg.PasswordIgnorePatterns, bufferCfg.SecretThreshold).Classify(record.Content).Type
+}
+
+// PreviewText generates the plain text preview of a record without markers.
+func PreviewText(record *database.Record, cfg *config.Config) string {
+	markup := preview.Text(record.PreviewEntry(false, ""), cfg)
+	return strings.TrimSpace(utils.StripMarkup(markup))
+}
+
+// WriteListJSON writes the items of a listing as an indented ListDocument.
+func WriteListJSON(w io.Writer, listing *database.Listing, items []Item) error {
+	doc := ListDocument{
+		Version:    SchemaVersion,
+		Buffer:     listing.Buffer,
+		BufferName: listing.BufferName,
+		Entries:    items,
+	}
+	if doc.Entries == nil {
+		doc.Entries = []Item{}
+	}
+	return writeJSON(w, doc)
+}
+
+// WriteGetJSON writes a record with its content as a GetDocument.
+func WriteGetJSON(w io.Writer, record *database.Record, cfg *config.Config) error {
+	var doc GetDocument
+	doc.Version = SchemaVersion
+	doc.Entry.Item = NewItem(record, PreviewText(record, cfg), cfg)
+	if utf8.Valid(record.Content) {
+		content := string(record.Content)
+		doc.Entry.Content = &content
+	} else {
+		doc.Entry.ContentBase64 = record.Content
+	}
+	return writeJSON(w, doc)
+}
+
+// WriteTSV writes items as tab-separated lines with the columns
+// id, buffer, pinned (0/1), created_at (RFC 3339), size, type, source, source_app, preview, icon.
+// Tabs and line breaks in values are replaced with spaces.
+func WriteTSV(w io.Writer, items []Item) {
+	for _, item := range items {
+		pinned := 0
+		if item.Pinned {
+			pinned = 1
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			item.ID, item.Buffer, pinned, item.CreatedAt.Format(time.RFC3339), item.Size, item.Type,
+			tsvField(item.Source), tsvField(item.SourceApp), tsvField(item.Preview), tsvField(item.Icon))
+	}
+}
+
+// tsvReplacer removes characters that would break TSV lines
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+func tsvField(value string) string {
+	return tsvReplacer.Replace(value)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package frontend
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/utils"
+)
+
+// writeDmenu writes a listing as plain text lines for dmenu-style launchers.
+// Launchers print the selected line, the hidden ID in it identifies the entry.
+func writeDmenu(w io.Writer, listing *database.Listing, cfg *config.Config) {
+	for _, e := range Rows(listing) {
+		fmt.Fprintf(w, "%s%s\n", e.Text, utils.EncodeIDHidden(e.ID))
+	}
+}
+
+// writeIconDmenu writes plain text lines with rofi-style icon metadata, which fuzzel understands.
+func writeIconDmenu(w io.Writer, listing *database.Listing, cfg *config.Config) {
+	for _, e := range Rows(listing) {
+		row := e.Text + utils.EncodeIDHidden(e.ID)
+		if e.Icon != "" {
+			row += rofiIconSep + e.Icon
+		}
+		fmt.Fprintln(w, row)
+	}
+}
+
+// writeFzf writes a listing as "ID<tab>text" lines.
+// fzf hides the ID column and passes it to the preview command.
+func writeFzf(w io.Writer, listing *database.Listing, cfg *config.Config) {
+	for _, e := range Rows(listing) {
+		fmt.Fprintf(w, "%d\t%s\n", e.ID, e.Text)
+	}
+}
+
+// fzfCommand returns the fzf command line with a preview of the full entry content.
+func fzfCommand(prompt string) []string {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "clipbox"
+	}
+	preview := utils.ShellQuote(executable) + " --get {1}"
+	return []string{
+		"fzf", "--delimiter", "\t", "--with-nth", "2..", "--no-sort",
+		"--prompt", prompt + "> ", "--preview", preview, "--preview-window", "wrap",
+	}
+}
@@ -0,0 +1,58 @@
+package frontend
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"clipbox/config"
+	"clipbox/utils"
+)
+
+func TestWriteDmenu(t *testing.T) {
+	var buf bytes.Buffer
+	writeDmenu(&buf, testListing(), &config.Config{})
+	want := "new & shiny" + utils.EncodeIDHidden(9) + "\n" +
+		"old" + utils.EncodeIDHidden(5) + "\n" +
+		"pinned" + utils.EncodeIDHidden(2) + "\n"
+	if buf.String() != want {
+		t.Errorf("writeDmenu() = %q, want %q", buf.String(), want)
+	}
+
+	// A launcher prints the selected lines unchanged
+	ids, err := ParseSelection(buf.String())
+	if err != nil || !slices.Equal(ids, []int{9, 5, 2}) {
+		t.Errorf("ParseSelection(writeDmenu()) = %v, %v, want 9, 5, 2", ids, err)
+	}
+}
+
+func TestWriteIconDmenu(t *testing.T) {
+	var buf bytes.Buffer
+	writeIconDmenu(&buf, testListing(), &config.Config{})
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("writeIconDmenu() wrote %d lines, want 3", len(lines))
+	}
+	if want := "new & shiny" + utils.EncodeIDHidden(9) + "\x00icon\x1f/tmp/9.png"; lines[0] != want {
+		t.Errorf("writeIconDmenu() row with icon = %q, want %q", lines[0], want)
+	}
+	if want := "old" + utils.EncodeIDHidden(5); lines[1] != want {
+		t.Errorf("writeIconDmenu() row without icon = %q, want %q", lines[1], want)
+	}
+}
+
+func TestWriteFzf(t *testing.T) {
+	var buf bytes.Buffer
+	writeFzf(&buf, testListing(), &config.Config{})
+	if want := "9\tnew & shiny\n5\told\n2\tpinned\n"; buf.String() != want {
+		t.Errorf("writeFzf() = %q, want %q", buf.String(), want)
+	}
+	ids, err := ParseSelection("5\told\n")
+	if err != nil || !slices.Equal(ids, []int{5}) {
+		t.Errorf("ParseSelection of an fzf row = %v, %v, want 5", ids, err)
+	}
+}
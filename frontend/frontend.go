@@ -0,0 +1,129 @@
+package frontend
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/utils"
+)
+
+// Frontend renders listings for a launcher and runs it to pick an entry.
+type Frontend struct {
+	Name string
+	// Render writes the listing as printed by --list
+	Render func(w io.Writer, listing *database.Listing, cfg *config.Config)
+	// Menu writes the listing as input for the launcher run by --pick
+	Menu func(w io.Writer, listing *database.Listing, cfg *config.Config)
+	// Command returns the launcher command line used by --pick
+	Command func(prompt string) []string
+}
+
+var frontends = map[string]Frontend{
+	"rofi": {
+		Name:   "rofi",
+		Render: WriteRofi,
+		Menu:   writeRofiDmenu,
+		Command: func(prompt string) []string {
+			return []string{"rofi", "-dmenu", "-i", "-markup-rows", "-show-icons", "-p", prompt}
+		},
+	},
+	"fuzzel": {
+		Name:   "fuzzel",
+		Render: writeIconDmenu,
+		Menu:   writeIconDmenu,
+		Command: func(prompt string) []string {
+			return []string{"fuzzel", "--dmenu", "--prompt", prompt + "> "}
+		},
+	},
+	"wofi": {
+		Name:   "wofi",
+		Render: writeDmenu,
+		Menu:   writeDmenu,
+		Command: func(prompt string) []string {
+			return []string{"wofi", "--dmenu", "--prompt", prompt}
+		},
+	},
+	"bemenu": {
+		Name:   "bemenu",
+		Render: writeDmenu,
+		Menu:   writeDmenu,
+		Command: func(prompt string) []string {
+			return []string{"bemenu", "-i", "-p", prompt}
+		},
+	},
+	"dmenu": {
+		Name:   "dmenu",
+		Render: writeDmenu,
+		Menu:   writeDmenu,
+		Command: func(prompt string) []string {
+			return []string{"dmenu", "-i", "-p", prompt}
+		},
+	},
+	"fzf": {
+		Name:    "fzf",
+		Render:  writeFzf,
+		Menu:    writeFzf,
+		Command: fzfCommand,
+	},
+}
+
+// Get returns the frontend with the given name.
+func Get(name string) (Frontend, error) {
+	f, ok := frontends[name]
+	if !ok {
+		return Frontend{}, fmt.Errorf("unknown frontend: %s (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return f, nil
+}
+
+// Names returns the names of all frontends in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(frontends))
+	for name := range frontends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rows returns the entries of a listing for launchers without a pinned section:
+// all entries, followed by pinned entries beyond the limit.
+func Rows(listing *database.Listing) []database.Entry {
+	rows := slices.Clone(listing.Entries)
+	for _, e := range listing.Pinned {
+		if !slices.ContainsFunc(listing.Entries, func(listed database.Entry) bool { return listed.ID == e.ID }) {
+			rows = append(rows, e)
+		}
+	}
+	return rows
+}
+
+// ParseSelection returns the IDs of the rows printed by a launcher.
+// Rows either start with "ID\t" (fzf) or end with the hidden ID encoding.
+func ParseSelection(output string) ([]int, error) {
+	var ids []int
+	for _, line := range strings.Split(output, "\n") {
+		if idStr, _, ok := strings.Cut(line, "\t"); ok {
+			if id, err := strconv.Atoi(idStr); err == nil && id > 0 {
+				ids = append(ids, id)
+				continue
+			}
+		}
+		if id, ok := utils.DecodeIDHidden(line); ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no entry ID in the selected rows")
+	}
+	return ids, nil
+}
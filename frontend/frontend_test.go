@@ -0,0 +1,86 @@
+package frontend
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"slices"
+	"testing"
+
+	"clipbox/database"
+	"clipbox/utils"
+)
+
+// testListing has two entries and a pinned entry beyond the limit
+func testListing() *database.Listing {
+	pinned := database.Entry{ID: 2, Pinned: true, Markup: "<b>pinned</b>", Text: "pinned"}
+	return &database.Listing{
+		Buffer:     1,
+		BufferName: "Main",
+		Entries: []database.Entry{
+			{ID: 9, Markup: "new &amp; shiny", Text: "new & shiny", Icon: "/tmp/9.png"},
+			{ID: 5, Markup: "old", Text: "old"},
+		},
+		Pinned: []database.Entry{pinned},
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []int
+		wantErr bool
+	}{
+		{"hidden id", "hello" + utils.EncodeIDHidden(42) + "\n", []int{42}, false},
+		{"fzf", "17\thello\n", []int{17}, false},
+		{"several rows", "a" + utils.EncodeIDHidden(3) + "\nb" + utils.EncodeIDHidden(8) + "\n", []int{3, 8}, false},
+		{"fzf and hidden id", "4\ta\nb" + utils.EncodeIDHidden(6), []int{4, 6}, false},
+		{"tab in text", "a\tb" + utils.EncodeIDHidden(11), []int{11}, false},
+		{"hidden id inside the text", "a" + utils.EncodeIDHidden(5) + " copied row", nil, true},
+		{"no id", "typed text\n", nil, true},
+		{"fzf zero", "0\thello", nil, true},
+		{"empty", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelection(tt.output)
+			if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+				t.Errorf("ParseSelection(%q) = %v, %v, want %v, error %v", tt.output, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRows(t *testing.T) {
+	listing := testListing()
+	var ids []int
+	for _, e := range Rows(listing) {
+		ids = append(ids, e.ID)
+	}
+	if want := []int{9, 5, 2}; !slices.Equal(ids, want) {
+		t.Errorf("Rows() = %v, want %v", ids, want)
+	}
+
+	// Pinned entries within the limit are not repeated
+	listing.Entries = append(listing.Entries, listing.Pinned[0])
+	ids = nil
+	for _, e := range Rows(listing) {
+		ids = append(ids, e.ID)
+	}
+	if want := []int{9, 5, 2}; !slices.Equal(ids, want) {
+		t.Errorf("Rows() with a listed pinned entry = %v, want %v", ids, want)
+	}
+}
+
+func TestGet(t *testing.T) {
+	for _, name := range Names() {
+		f, err := Get(name)
+		if err != nil || f.Name != name || f.Render == nil || f.Menu == nil || f.Command == nil {
+			t.Errorf("Get(%q) = %+v, %v, want a complete frontend", name, f, err)
+		}
+	}
+	if _, err := Get("rofi-wayland"); err == nil {
+		t.Error("Get of an unknown frontend succeeded")
+	}
+}
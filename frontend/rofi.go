@@ -0,0 +1,66 @@
+package frontend
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/utils"
+)
+
+const (
+	rofiIconSep = "\x00icon\x1f"
+	rofiInfoSep = "\x00info\x1f"
+)
+
+// WriteRofi writes a listing in rofi script mode format.
+// Entries are sorted by ID (newest first), with pinned entries repeated at the end.
+func WriteRofi(w io.Writer, listing *database.Listing, cfg *config.Config) {
+	// Rofi script mode configuration
+	fmt.Fprint(w, "\x00use-hot-keys\x1ftrue\n")
+	fmt.Fprint(w, "\x00keep-selection\x1ftrue\n")
+	fmt.Fprint(w, "\x00markup-rows\x1ftrue\n")
+	fmt.Fprintf(w, "\x00prompt\x1f%s\n", listing.BufferName)
+
+	for _, e := range listing.Entries {
+		writeRofiRow(w, e)
+	}
+
+	if len(listing.Pinned) > 0 && len(listing.Entries) > 0 {
+		separator := strings.Repeat("—", cfg.SeparatorLength)
+		fmt.Fprintf(w, "%s%s0\n", separator, rofiInfoSep)
+	}
+	for _, e := range listing.Pinned {
+		writeRofiRow(w, e)
+	}
+
+	if len(listing.Entries) == 0 && len(listing.Pinned) == 0 {
+		fmt.Fprintf(w, " (No entries in buffer %d)%s0\n", listing.Buffer, rofiInfoSep)
+	}
+}
+
+// writeRofiRow writes an entry as a rofi script mode row.
+// The hidden ID is kept in the text for launchers that only return the row text.
+func writeRofiRow(w io.Writer, e database.Entry) {
+	row := e.Markup + utils.EncodeIDHidden(e.ID)
+	if e.Icon != "" {
+		row += rofiIconSep + e.Icon
+	}
+	fmt.Fprintf(w, "%s%s%d\n", row, rofiInfoSep, e.ID)
+}
+
+// writeRofiDmenu writes a listing as rofi -dmenu input with markup and icons.
+func writeRofiDmenu(w io.Writer, listing *database.Listing, cfg *config.Config) {
+	for _, e := range Rows(listing) {
+		row := e.Markup + utils.EncodeIDHidden(e.ID)
+		if e.Icon != "" {
+			row += rofiIconSep + e.Icon
+		}
+		fmt.Fprintln(w, row)
+	}
+}
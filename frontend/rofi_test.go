@@ -0,0 +1,49 @@
+package frontend
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/utils"
+)
+
+func TestWriteRofi(t *testing.T) {
+	var buf bytes.Buffer
+	WriteRofi(&buf, testListing(), &config.Config{SeparatorLength: 3})
+	want := "\x00use-hot-keys\x1ftrue\n" +
+		"\x00keep-selection\x1ftrue\n" +
+		"\x00markup-rows\x1ftrue\n" +
+		"\x00prompt\x1fMain\n" +
+		"new &amp; shiny" + utils.EncodeIDHidden(9) + "\x00icon\x1f/tmp/9.png\x00info\x1f9\n" +
+		"old" + utils.EncodeIDHidden(5) + "\x00info\x1f5\n" +
+		"———\x00info\x1f0\n" +
+		"<b>pinned</b>" + utils.EncodeIDHidden(2) + "\x00info\x1f2\n"
+	if buf.String() != want {
+		t.Errorf("WriteRofi() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteRofiEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	WriteRofi(&buf, &database.Listing{Buffer: 3, BufferName: "Work"}, &config.Config{SeparatorLength: 3})
+	if !strings.HasSuffix(buf.String(), " (No entries in buffer 3)\x00info\x1f0\n") {
+		t.Errorf("WriteRofi() of an empty buffer = %q, want the no entries row", buf.String())
+	}
+}
+
+func TestWriteRofiDmenu(t *testing.T) {
+	var buf bytes.Buffer
+	writeRofiDmenu(&buf, testListing(), &config.Config{})
+	want := "new &amp; shiny" + utils.EncodeIDHidden(9) + "\x00icon\x1f/tmp/9.png\n" +
+		"old" + utils.EncodeIDHidden(5) + "\n" +
+		"<b>pinned</b>" + utils.EncodeIDHidden(2) + "\n"
+	if buf.String() != want {
+		t.Errorf("writeRofiDmenu() = %q, want %q", buf.String(), want)
+	}
+}
@@ -13,22 +13,23 @@ import (
 	_ "image/jpeg"
 )
 
-// DetectImageFormat checks if data is an image and returns its format (jpg, png, gif)
+// DetectImageFormat checks if data is a JPEG, PNG or GIF image, so icons can be created for it.
+// Returns jpg, png or gif, which is also the file extension of the format.
+// Other images are not reported, even if a decoder for them is registered,
+// detect.DetectBinaryFormat identifies formats such as WebP or TIFF.
 func DetectImageFormat(data []byte) (string, bool) {
 	limitedReader := io.LimitReader(bytes.NewReader(data), 1024*1024)
 	_, format, err := image.DecodeConfig(limitedReader)
 	if err != nil {
 		return "", false
 	}
-	format = strings.ToLower(format)
-	switch format {
+	switch strings.ToLower(format) {
 	case "jpeg", "jpg":
 		return "jpg", true
 	case "png":
 		return "png", true
 	case "gif":
 		return "gif", true
-	default:
-		return format, true
 	}
+	return "", false
 }
@@ -0,0 +1,66 @@
+package image
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+)
+
+// encode returns a small image in the given format
+func encode(t *testing.T, format string) []byte {
+	t.Helper()
+	img := image.NewPaletted(image.Rect(0, 0, 4, 4), []color.Color{color.Black, color.White})
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectImageFormat(t *testing.T) {
+	// A decoder registered by some other package must not turn its format into an image entry
+	image.RegisterFormat("fake", "FAKE", func(io.Reader) (image.Image, error) {
+		return nil, errors.New("not implemented")
+	}, func(io.Reader) (image.Config, error) {
+		return image.Config{Width: 1, Height: 1}, nil
+	})
+
+	tests := []struct {
+		name   string
+		data   []byte
+		format string // Empty if data is no image
+	}{
+		{"png", encode(t, "png"), "png"},
+		{"jpeg", encode(t, "jpeg"), "jpg"},
+		{"gif", encode(t, "gif"), "gif"},
+		{"registered format", []byte("FAKE image data"), ""},
+		{"truncated png", encode(t, "png")[:12], ""},
+		{"webp", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), ""},
+		{"text", []byte("hello world"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		format, ok := DetectImageFormat(tt.data)
+		if format != tt.format || ok != (tt.format != "") {
+			t.Errorf("%s: DetectImageFormat = %q, %v, want %q", tt.name, format, ok, tt.format)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
 	"image/png"
 	"os"
 	"path/filepath"
@@ -14,7 +16,10 @@ import (
 	"clipbox/config"
 )
 
-const maxIconSize = 64 // Maximum icon size in pixels
+const (
+	maxIconSize = 64 // Maximum icon size in pixels
+	swatchSize  = 32 // Size of color swatch icons in pixels
+)
 
 // GetIconsDir returns the path to the icons directory (next to the database file)
 func GetIconsDir() (string, error) {
@@ -100,6 +105,28 @@ func ProcessImageIcon(id int, data []byte) (string, error) {
 	resized := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
 	ScaleImage(resized, img)
 
+	return writeIcon(iconsDir, id, resized)
+}
+
+// ProcessColorIcon saves a solid color swatch as the PNG icon of an entry
+func ProcessColorIcon(id int, c color.Color) (string, error) {
+	iconsDir, err := GetIconsDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get icons dir: %w", err)
+	}
+
+	if err := os.MkdirAll(iconsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create icons dir: %w", err)
+	}
+
+	swatch := image.NewNRGBA(image.Rect(0, 0, swatchSize, swatchSize))
+	draw.Draw(swatch, swatch.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+
+	return writeIcon(iconsDir, id, swatch)
+}
+
+// writeIcon encodes img as the PNG icon of an entry and returns its absolute path
+func writeIcon(iconsDir string, id int, img image.Image) (string, error) {
 	iconPath := filepath.Join(iconsDir, fmt.Sprintf("%d.png", id))
 	file, err := os.Create(iconPath)
 	if err != nil {
@@ -107,7 +134,7 @@ func ProcessImageIcon(id int, data []byte) (string, error) {
 	}
 	defer file.Close()
 
-	if err := png.Encode(file, resized); err != nil {
+	if err := png.Encode(file, img); err != nil {
 		return "", fmt.Errorf("failed to encode PNG: %w", err)
 	}
 
@@ -0,0 +1,144 @@
+package ipc
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"clipbox/database"
+)
+
+const dialTimeout = 500 * time.Millisecond
+
+// Client sends requests to a running clipbox server.
+type Client struct {
+	SocketPath string
+}
+
+// NewClient returns a client for the default socket path.
+func NewClient() *Client {
+	return &Client{SocketPath: SocketPath()}
+}
+
+// Available reports whether the socket exists and belongs to the current user.
+func (c *Client) Available() bool {
+	return checkSocket(c.SocketPath) == nil
+}
+
+// Do sends a single request and waits for the response.
+func (c *Client) Do(req Request) (*Response, error) {
+	if err := checkSocket(c.SocketPath); err != nil {
+		return nil, fmt.Errorf("refusing to connect to server: %w", err)
+	}
+	conn, err := net.DialTimeout("unix", c.SocketPath, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if !resp.OK {
+		return nil, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
+
+// Listing returns the entries of the current buffer, optionally filtered by source application.
+func (c *Client) Listing(limit int, app string) (*database.Listing, error) {
+	resp, err := c.Do(Request{Op: OpList, Limit: limit, App: app})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Listing, nil
+}
+
+// Get returns an entry including its content.
+func (c *Client) Get(id int) (*database.Record, error) {
+	resp, err := c.Do(Request{Op: OpGet, ID: id})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Record, nil
+}
+
+// Records returns several entries including their content, in the order of ids.
+func (c *Client) Records(ids []int) ([]*database.Record, error) {
+	resp, err := c.Do(Request{Op: OpRecords, IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Records, nil
+}
+
+// Store reads content from r and saves it to a buffer, 0 is the current buffer.
+// The source application and the offered type are detected here, in the session of the caller.
+func (c *Client) Store(r io.Reader, source string, bufferID int) error {
+	content, err := database.ReadContent(r)
+	if err != nil {
+		return err
+	}
+	capture := database.DetectCapture(content, source)
+	_, err = c.Do(Request{Op: OpStore, Content: content, Source: source, Buffer: bufferID, App: capture.App, MimeType: capture.MimeType})
+	return err
+}
+
+// TogglePin toggles the pinned status of an entry.
+func (c *Client) TogglePin(id int) error {
+	_, err := c.Do(Request{Op: OpPin, ID: id})
+	return err
+}
+
+// SetPinned sets the pinned status of an entry.
+func (c *Client) SetPinned(id int, pinned bool) error {
+	_, err := c.Do(Request{Op: OpPin, ID: id, Pinned: &pinned})
+	return err
+}
+
+// ToggleTemplate enables or disables placeholder expansion for an entry.
+func (c *Client) ToggleTemplate(id int) error {
+	_, err := c.Do(Request{Op: OpTemplate, ID: id})
+	return err
+}
+
+// Move moves an entry to another buffer.
+func (c *Client) Move(id int, bufferID int) error {
+	_, err := c.Do(Request{Op: OpMove, ID: id, Buffer: bufferID})
+	return err
+}
+
+// Delete deletes an entry.
+func (c *Client) Delete(id int) error {
+	_, err := c.Do(Request{Op: OpDelete, ID: id})
+	return err
+}
+
+// SwitchBuffer changes the active buffer to the specified ID (1-5).
+func (c *Client) SwitchBuffer(bufferID int) error {
+	_, err := c.Do(Request{Op: OpSwitchBuffer, Buffer: bufferID})
+	return err
+}
+
+// SwitchToNextBuffer switches to the next buffer cyclically.
+func (c *Client) SwitchToNextBuffer() error {
+	_, err := c.Do(Request{Op: OpSwitchBuffer, Direction: "next"})
+	return err
+}
+
+// SwitchToPreviousBuffer switches to the previous buffer cyclically.
+func (c *Client) SwitchToPreviousBuffer() error {
+	_, err := c.Do(Request{Op: OpSwitchBuffer, Direction: "prev"})
+	return err
+}
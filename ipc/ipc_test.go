@@ -0,0 +1,174 @@
+package ipc
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startServer serves the database in a temporary directory on a socket in another one.
+// config is written as config.conf.
+func startServer(t *testing.T, config string) *Client {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	configDir := filepath.Join(dir, "config", "clipbox")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.conf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), socketName)
+	listener, err := listen(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- serve(listener) }()
+	t.Cleanup(func() {
+		listener.Close()
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+	return &Client{SocketPath: socketPath}
+}
+
+func TestRoundTrip(t *testing.T) {
+	client := startServer(t, "")
+	if !client.Available() {
+		t.Fatal("Available() = false for a running server")
+	}
+
+	info, err := os.Stat(client.SocketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket permissions = %o, want 600", perm)
+	}
+
+	for _, content := range []string{"first", "second"} {
+		if err := client.Store(strings.NewReader(content), "", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listing, err := client.Listing(0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listing.Entries) != 2 {
+		t.Fatalf("Listing() returned %d entries, want 2", len(listing.Entries))
+	}
+	newest := listing.Entries[0].ID
+
+	record, err := client.Get(newest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(record.Content) != "second" {
+		t.Errorf("Get(%d) content = %q, want %q", newest, record.Content, "second")
+	}
+
+	records, err := client.Records([]int{listing.Entries[1].ID, newest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || string(records[0].Content) != "first" || string(records[1].Content) != "second" {
+		t.Errorf("Records() = %+v, want first and second", records)
+	}
+
+	if err := client.Delete(newest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(newest); err == nil {
+		t.Errorf("Get(%d) after Delete succeeded, want an error", newest)
+	}
+	if _, err := client.Do(Request{Op: "bogus"}); err == nil || !strings.Contains(err.Error(), "unknown op") {
+		t.Errorf("Do(bogus) error = %v, want unknown op", err)
+	}
+}
+
+func TestStoreSourceApp(t *testing.T) {
+	client := startServer(t, "source_app_command=echo kitty\n")
+
+	// The client detects the application, the server stores what it was sent
+	if err := client.Store(strings.NewReader("from the client"), "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(Request{Op: OpStore, Content: []byte("from firefox"), App: "firefox"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for app, want := range map[string]int{"kitty": 1, "firefox": 1, "": 2} {
+		listing, err := client.Listing(0, app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listing.Entries) != want {
+			t.Errorf("Listing(app=%q) returned %d entries, want %d", app, len(listing.Entries), want)
+		}
+	}
+}
+
+func TestListenRunningServer(t *testing.T) {
+	client := startServer(t, "")
+	if _, err := listen(client.SocketPath); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("listen on a live socket error = %v, want server already running", err)
+	}
+}
+
+func TestCheckSocket(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSocket(file); err == nil {
+		t.Error("checkSocket of a regular file succeeded")
+	}
+	if err := checkSocket(filepath.Join(dir, "missing")); err == nil {
+		t.Error("checkSocket of a missing socket succeeded")
+	}
+
+	shared := filepath.Join(dir, "shared")
+	if err := os.Mkdir(shared, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(shared, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := prepareSocketDir(filepath.Join(shared, socketName)); err == nil {
+		t.Error("prepareSocketDir of a world-writable directory succeeded")
+	}
+
+	client := &Client{SocketPath: filepath.Join(shared, socketName)}
+	if client.Available() {
+		t.Error("Available() = true without a socket")
+	}
+	if _, err := client.Do(Request{Op: OpList}); err == nil || !strings.Contains(err.Error(), "refusing") {
+		t.Errorf("Do() in a world-writable directory error = %v, want refusal", err)
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	if got := SocketPath(); got != "/run/user/1000/clipbox.sock" {
+		t.Errorf("SocketPath() = %q, want /run/user/1000/clipbox.sock", got)
+	}
+
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	want := filepath.Join(os.TempDir(), fmt.Sprintf("clipbox-%d", os.Getuid()), socketName)
+	if got := SocketPath(); got != want {
+		t.Errorf("SocketPath() without XDG_RUNTIME_DIR = %q, want %q", got, want)
+	}
+}
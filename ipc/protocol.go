@@ -0,0 +1,62 @@
+package ipc
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"clipbox/database"
+)
+
+const socketName = "clipbox.sock"
+
+// Operations supported by the socket protocol
+const (
+	OpList         = "list"
+	OpGet          = "get"
+	OpRecords      = "records"
+	OpStore        = "store"
+	OpPin          = "pin"
+	OpDelete       = "delete"
+	OpMove         = "move"
+	OpTemplate     = "toggle-template"
+	OpSwitchBuffer = "switch-buffer"
+)
+
+// Request is a single JSON line sent by a client.
+// Content is base64 encoded by encoding/json.
+type Request struct {
+	Op        string `json:"op"`
+	ID        int    `json:"id,omitempty"`
+	IDs       []int  `json:"ids,omitempty"` // Entries for records
+	Limit     int    `json:"limit,omitempty"`
+	App       string `json:"app,omitempty"` // Source application filter for list, focused application for store
+	Buffer    int    `json:"buffer,omitempty"`
+	Pinned    *bool  `json:"pinned,omitempty"`    // Status to set for pin, toggles if omitted
+	Direction string `json:"direction,omitempty"` // "next" or "prev" for switch-buffer
+	Content   []byte `json:"content,omitempty"`
+	Source    string `json:"source,omitempty"`    // "clipboard" (default) or "primary" for store
+	MimeType  string `json:"mime_type,omitempty"` // Type the stored content was offered as, see database.Capture
+}
+
+// Response is a single JSON line sent back by the server.
+type Response struct {
+	OK      bool               `json:"ok"`
+	Error   string             `json:"error,omitempty"`
+	Listing *database.Listing  `json:"listing,omitempty"`
+	Record  *database.Record   `json:"record,omitempty"`
+	Records []*database.Record `json:"records,omitempty"`
+}
+
+// SocketPath returns the path to the clipbox socket.
+// Uses $XDG_RUNTIME_DIR/clipbox.sock, or a private per-user directory in the temp dir if it is not set.
+func SocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = filepath.Join(os.TempDir(), fmt.Sprintf("clipbox-%d", os.Getuid()))
+	}
+	return filepath.Join(runtimeDir, socketName)
+}
@@ -0,0 +1,155 @@
+package ipc
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"clipbox/database"
+)
+
+const maxRequestSize = 16 * 1024 * 1024 // Content is base64 encoded, so it is larger than maxFileSize
+
+// Serve listens on the socket and handles requests until SIGINT or SIGTERM.
+func Serve(socketPath string) error {
+	listener, err := listen(socketPath)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	return serve(listener)
+}
+
+// listen creates the socket, which is only accessible to the current user.
+// A stale socket left by a crashed server is removed; a live one is an error.
+func listen(socketPath string) (net.Listener, error) {
+	if err := prepareSocketDir(socketPath); err != nil {
+		return nil, err
+	}
+	if _, err := os.Lstat(socketPath); err == nil {
+		if err := checkSocket(socketPath); err != nil {
+			return nil, fmt.Errorf("refusing to replace socket: %w", err)
+		}
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("server already running on %s", socketPath)
+		}
+		if err := os.Remove(socketPath); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+
+	// The umask makes the socket private from the moment it is created
+	oldMask := syscall.Umask(0177)
+	listener, err := net.Listen("unix", socketPath)
+	syscall.Umask(oldMask)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on socket: %w", err)
+	}
+	return listener, nil
+}
+
+// serve handles connections until the listener is closed.
+func serve(listener net.Listener) error {
+	// SQLite access is serialized, requests are short
+	var mu sync.Mutex
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go serveConn(conn, &mu)
+	}
+}
+
+// serveConn answers newline-delimited JSON requests until the client disconnects.
+func serveConn(conn net.Conn, mu *sync.Mutex) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxRequestSize)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else {
+			mu.Lock()
+			resp = handle(req)
+			mu.Unlock()
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle maps a request onto the database operations.
+func handle(req Request) Response {
+	var err error
+	resp := Response{}
+
+	switch req.Op {
+	case OpList:
+		resp.Listing, err = database.LoadListing(req.Limit, req.App)
+	case OpGet:
+		resp.Record, err = database.GetRecord(req.ID)
+	case OpRecords:
+		resp.Records, err = database.GetRecords(req.IDs)
+	case OpStore:
+		err = database.StoreCaptured(req.Content, req.Source, req.Buffer, database.Capture{App: req.App, MimeType: req.MimeType})
+	case OpPin:
+		if req.Pinned != nil {
+			err = database.SetPinned(req.ID, *req.Pinned)
+		} else {
+			err = database.TogglePin(req.ID)
+		}
+	case OpDelete:
+		err = database.DeleteEntry(req.ID)
+	case OpTemplate:
+		err = database.ToggleTemplate(req.ID)
+	case OpMove:
+		err = database.MoveEntry(req.ID, req.Buffer)
+	case OpSwitchBuffer:
+		switch req.Direction {
+		case "":
+			err = database.SwitchBuffer(req.Buffer)
+		case "next":
+			err = database.SwitchToNextBuffer()
+		case "prev":
+			err = database.SwitchToPreviousBuffer()
+		default:
+			err = fmt.Errorf("unknown direction: %s", req.Direction)
+		}
+	default:
+		err = fmt.Errorf("unknown op: %s", req.Op)
+	}
+
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	resp.OK = true
+	return resp
+}
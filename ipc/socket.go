@@ -0,0 +1,70 @@
+package ipc
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// checkOwner returns the file info of path if it is owned by the current user.
+// Lstat is used, so a symlink planted by another user is not followed.
+func checkOwner(path string) (os.FileInfo, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		return nil, fmt.Errorf("%s is not owned by the current user", path)
+	}
+	return info, nil
+}
+
+// checkSocketDir verifies that dir is a directory of the current user that other users cannot write to.
+func checkSocketDir(dir string) error {
+	info, err := checkOwner(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("%s is writable by other users", dir)
+	}
+	return nil
+}
+
+// checkSocket verifies that the socket and its directory belong to the current user,
+// so requests are never sent to a socket created by someone else.
+func checkSocket(socketPath string) error {
+	if err := checkSocketDir(filepath.Dir(socketPath)); err != nil {
+		return err
+	}
+	info, err := checkOwner(socketPath)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s is not a socket", socketPath)
+	}
+	return nil
+}
+
+// prepareSocketDir creates the directory of the socket with 0700 permissions
+// if it does not exist and verifies it otherwise.
+func prepareSocketDir(socketPath string) error {
+	dir := filepath.Dir(socketPath)
+	if err := os.Mkdir(dir, 0700); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := checkSocketDir(dir); err != nil {
+		return fmt.Errorf("unsafe socket directory: %w", err)
+	}
+	return nil
+}
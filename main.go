@@ -15,12 +15,15 @@ package main
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"net/url"
 	"os"
-	"strconv"
 
 	"clipbox/database"
 	"clipbox/maintenance"
+	"clipbox/paste"
 	"clipbox/utils"
 )
 
@@ -30,149 +33,70 @@ func main() {
 	// Handle --version and -v flags first
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
-		case "--version", "-v":
+		case "--version", "-v", "version":
 			fmt.Printf("clipbox %s\n", version)
 			os.Exit(0)
+		case "--help", "-h", "help":
+			printUsage()
+			os.Exit(0)
 		}
 	}
 
-	rofiRetv := os.Getenv("ROFI_RETV")
+	// Listings with stale previews rebuild them in a separate process
+	database.StartRebuild = maintenance.StartRebuild
 
-	// Handle rofi kb-custom keys
-	if rofiRetv != "" {
-		retv, err := strconv.Atoi(rofiRetv)
-		if err == nil {
-			switch retv {
-			case 10: // kb-custom-1: toggle pin
-				if len(os.Args) >= 2 && os.Args[1] != "" {
-					id, err := utils.ExtractID(os.Args[1])
-					if err == nil && id > 0 {
-						if err := database.TogglePin(id); err != nil {
-							fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-							os.Exit(1)
-						}
-						if err := database.List(0); err != nil {
-							fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-							os.Exit(1)
-						}
-						return
-					}
-				}
-				if err := database.List(0); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				return
-			case 11, 12, 13, 14, 15: // kb-custom-2 to kb-custom-6: switch buffer
-				bufferID := retv - 10
-				if err := database.SwitchBuffer(bufferID); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				if err := database.List(0); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				return
-			case 16: // kb-custom-7: delete entry
-				if len(os.Args) >= 2 && os.Args[1] != "" {
-					id, err := utils.ExtractID(os.Args[1])
-					if err == nil && id > 0 {
-						if err := database.DeleteEntry(id); err != nil {
-							fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-							os.Exit(1)
-						}
-						if err := database.List(0); err != nil {
-							fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-							os.Exit(1)
-						}
-						return
-					}
-				}
-				if err := database.List(0); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				return
-			case 17: // kb-custom-8: switch to previous buffer
-				if err := database.SwitchToPreviousBuffer(); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				if err := database.List(0); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				return
-			case 18: // kb-custom-9: switch to next buffer
-				if err := database.SwitchToNextBuffer(); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				if err := database.List(0); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				return
-			}
-		}
-	}
+	// --remote may appear anywhere, rofi appends the selected entry after it
+	args, remote := removeFlag(os.Args[1:], "--remote")
+	b := selectBackend(remote)
 
-	var command string
-	if len(os.Args) < 2 {
-		command = "--list"
-	} else {
-		command = os.Args[1]
-	}
-
-	switch command {
-	case "--store":
-		if err := database.Store(); err != nil {
+	if retv, ok := rofiRetv(); ok {
+		if err := runRofi(b, retv, args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-	case "--list":
-		limit := 0
-		if len(os.Args) > 2 {
-			if _, err := fmt.Sscanf(os.Args[2], "%d", &limit); err != nil {
-				fmt.Fprintf(os.Stderr, "Invalid limit: %s\n", os.Args[2])
-				os.Exit(1)
-			}
-		}
-		if err := database.List(limit); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
-	case "--rebuild-previews":
-		if err := maintenance.RebuildAllPreviews(); err != nil {
+		return
+	}
+
+	if len(args) == 0 {
+		if err := listRofi(b, 0, ""); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-	case "--vacuum":
-		if err := maintenance.VacuumDB(); err != nil {
+		return
+	}
+
+	if args[0] == paste.DeferredCommand {
+		if err := runPasteDeferred(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-	default:
-		// Rofi modi mode: handle selection
-		if len(os.Args) >= 2 && command != "" {
-			id, err := utils.ExtractID(command)
-			if err == nil && id > 0 {
-				content, err := database.GetContentByID(id)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				if err := utils.CopyToClipboard(content); err != nil {
-					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-					os.Exit(1)
-				}
-				os.Exit(0)
+		return
+	}
+
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		// A row printed by a launcher selects its entry, as in rofi mode
+		if id, err := utils.ExtractID(args[0]); err == nil && id > 0 && len(args) == 1 {
+			if err := selectEntry(b, id, false, url.Values{}); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
+			return
 		}
-		if err := database.List(0); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
-		}
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+		printUsage()
+		os.Exit(2)
+	}
+
+	err := cmd.run(b, newFlagSet(cmd), args[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 }
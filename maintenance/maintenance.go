@@ -6,11 +6,13 @@ package maintenance
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"syscall"
 
 	"clipbox/config"
 	"clipbox/database"
-	"clipbox/image"
-	"clipbox/preview"
 	"clipbox/utils"
 )
 
@@ -56,8 +58,29 @@ func VacuumDB() error {
 	return nil
 }
 
-// RebuildAllPreviews regenerates preview strings for all entries using current config
-func RebuildAllPreviews() error {
+// StartRebuild starts "clipbox rebuild-previews --if-stale" as a detached process,
+// so listings don't wait for the previews of all entries.
+func StartRebuild() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find clipbox executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, "rebuild-previews", "--if-stale")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	// Otherwise the process would run in rofi script mode
+	cmd.Env = slices.DeleteFunc(os.Environ(), func(env string) bool {
+		return strings.HasPrefix(env, "ROFI_")
+	})
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start rebuild process: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// RebuildAllPreviews regenerates preview strings for all entries using current config.
+// If ifStale is true, nothing is done when the previews match the current preview settings.
+func RebuildAllPreviews(ifStale bool) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -69,69 +92,20 @@ func RebuildAllPreviews() error {
 	}
 	defer db.Close()
 
-	query := `SELECT id, is_pinned, content FROM clipboard`
-	rows, err := db.Query(query)
-	if err != nil {
-		return fmt.Errorf("failed to query entries: %w", err)
-	}
-
-	type entry struct {
-		id       int
-		isPinned int
-		content  []byte
-	}
-
-	var entries []entry
-	for rows.Next() {
-		var e entry
-		if err := rows.Scan(&e.id, &e.isPinned, &e.content); err != nil {
-			rows.Close()
-			return fmt.Errorf("failed to scan row: %w", err)
+	if ifStale {
+		stale, err := database.PreviewsStale(db, cfg)
+		if err != nil {
+			return err
+		}
+		if !stale {
+			fmt.Fprintf(os.Stderr, "Previews are up to date\n")
+			return nil
 		}
-		entries = append(entries, e)
-	}
-	rows.Close()
-
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	updateStmt, err := db.Prepare("UPDATE clipboard SET preview = ? WHERE id = ?")
+	updatedCount, err := database.RebuildPreviews(db, cfg, true)
 	if err != nil {
-		return fmt.Errorf("failed to prepare update statement: %w", err)
-	}
-	defer updateStmt.Close()
-
-	updatedCount := 0
-	for _, e := range entries {
-		var hasIcon bool
-		var iconPath string
-		if cfg.ShowImageIcons {
-			// Check if icon already exists
-			if path, ok := image.GetIconPath(e.id); ok {
-				hasIcon = true
-				iconPath = path
-			} else {
-				// Icon doesn't exist, check if content is an image and create icon
-				if _, isImage := image.DetectImageFormat(e.content); isImage {
-					path, err := image.ProcessImageIcon(e.id, e.content)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to process image icon for id %d: %v\n", e.id, err)
-					} else {
-						hasIcon = true
-						iconPath = path
-					}
-				}
-			}
-		}
-
-		previewText := preview.GeneratePreview(e.id, e.content, e.isPinned, hasIcon, iconPath, cfg)
-		_, err := updateStmt.Exec(previewText, e.id)
-		if err != nil {
-			return fmt.Errorf("failed to update preview for id %d: %w", e.id, err)
-		}
-
-		updatedCount++
+		return err
 	}
 
 	fmt.Fprintf(os.Stderr, "Updated %d previews\n", updatedCount)
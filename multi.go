@@ -0,0 +1,199 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"clipbox/config"
+	"clipbox/frontend"
+	"clipbox/utils"
+)
+
+// Multi-select actions
+const (
+	multiMerge  = "merge"
+	multiPin    = "pin"
+	multiDelete = "delete"
+	multiMove   = "move"
+)
+
+// runMultiSelect shows the current buffer in rofi -dmenu -multi-select and applies
+// the action bound to the key used: Enter merges, kb-custom-1 pins,
+// kb-custom-2 to kb-custom-6 move to buffers 1-5 and kb-custom-7 deletes.
+func runMultiSelect(b backend) error {
+	listing, err := b.Listing(0, "")
+	if err != nil {
+		return err
+	}
+
+	var input bytes.Buffer
+	for _, e := range frontend.Rows(listing) {
+		fmt.Fprintf(&input, "%s%s\n", e.Markup, utils.EncodeIDHidden(e.ID))
+	}
+
+	cmd := exec.Command("rofi", "-dmenu", "-multi-select", "-markup-rows", "-i", "-p", listing.BufferName)
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to run rofi: %w", err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	var action, arg string
+	switch {
+	case exitCode == 0:
+		action = multiMerge
+	case exitCode == 10:
+		action = multiPin
+	case exitCode >= 11 && exitCode <= 15:
+		action, arg = multiMove, strconv.Itoa(exitCode-10)
+	case exitCode == 16:
+		action = multiDelete
+	default:
+		// Cancelled or unbound key
+		return nil
+	}
+
+	ids := utils.DecodeIDsHidden(string(output))
+	if len(ids) == 0 {
+		return nil
+	}
+	return runMulti(b, action, arg, ids)
+}
+
+// runMultiFromReader applies an action to the entries of the rows read from r,
+// e.g. the output of any launcher in multi-select mode.
+func runMultiFromReader(b backend, action string, arg string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read selection: %w", err)
+	}
+	ids, err := utils.ExtractIDs(string(data))
+	if err != nil {
+		return err
+	}
+	return runMulti(b, action, arg, ids)
+}
+
+// runMulti applies an action to several entries.
+func runMulti(b backend, action string, arg string, ids []int) error {
+	switch action {
+	case multiMerge:
+		return mergeEntries(b, ids)
+	case multiPin:
+		return pinEntries(b, ids)
+	case multiDelete:
+		return applyEach(ids, b.Delete)
+	case multiMove:
+		bufferID, err := strconv.Atoi(arg)
+		if err != nil || bufferID < 1 || bufferID > 5 {
+			return fmt.Errorf("invalid buffer: %s", arg)
+		}
+		return applyEach(ids, func(id int) error { return b.Move(id, bufferID) })
+	default:
+		return fmt.Errorf("unknown action: %s", action)
+	}
+}
+
+// mergeEntries joins entries in the order they were copied, copies the result
+// to the clipboard and stores it as a new entry.
+func mergeEntries(b backend, ids []int) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+
+	parts := make([][]byte, 0, len(sorted))
+	for _, id := range sorted {
+		record, err := b.Get(id)
+		if err != nil {
+			return err
+		}
+		// Line breaks at the end of copied lines would double the separator
+		parts = append(parts, bytes.TrimRight(record.Content, "\r\n"))
+	}
+	merged := bytes.Join(parts, []byte(cfg.MergeSeparator))
+
+	if err := utils.CopyToClipboard(merged, cfg.CopyToPrimary); err != nil {
+		return err
+	}
+	return b.Store(bytes.NewReader(merged), utils.SourceClipboard, 0)
+}
+
+// pinEntries pins all entries, or unpins them if all are already pinned.
+func pinEntries(b backend, ids []int) error {
+	listing, err := b.Listing(0, "")
+	if err != nil {
+		return err
+	}
+
+	pinned := make(map[int]bool)
+	for _, e := range listing.Pinned {
+		pinned[e.ID] = true
+	}
+
+	allPinned := true
+	for _, id := range ids {
+		if !pinned[id] {
+			allPinned = false
+			break
+		}
+	}
+
+	return applyEach(ids, func(id int) error { return b.SetPinned(id, !allPinned) })
+}
+
+// applyEach calls apply for each entry and stops at the first error.
+// Entries changed before are not restored, so the error lists them.
+func applyEach(ids []int, apply func(id int) error) error {
+	for i, id := range ids {
+		if err := apply(id); err != nil {
+			if i == 0 {
+				return err
+			}
+			applied := make([]string, i)
+			for j, id := range ids[:i] {
+				applied[j] = strconv.Itoa(id)
+			}
+			return fmt.Errorf("failed at entry %d, entries %s were already changed: %w", id, strings.Join(applied, ", "), err)
+		}
+	}
+	return nil
+}
+
+// parseMultiArgs splits "--multi ACTION [BUFFER]" arguments.
+func parseMultiArgs(args []string) (action string, arg string, err error) {
+	if len(args) == 0 {
+		return "", "", errors.New("missing action")
+	}
+	action = args[0]
+	if action == multiMove {
+		if len(args) < 2 {
+			return "", "", errors.New("missing buffer for move")
+		}
+		arg = args[1]
+	}
+	if !slices.Contains([]string{multiMerge, multiPin, multiDelete, multiMove}, action) {
+		return "", "", fmt.Errorf("unknown action: %s", action)
+	}
+	return action, strings.TrimSpace(arg), nil
+}
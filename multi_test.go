@@ -0,0 +1,44 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyEach(t *testing.T) {
+	tests := []struct {
+		name    string
+		failing int
+		want    []int
+		wantErr string
+	}{
+		{"all applied", 0, []int{3, 5, 7}, ""},
+		{"first fails", 3, nil, "entry not found"},
+		{"last fails", 7, []int{3, 5}, "failed at entry 7, entries 3, 5 were already changed: entry not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []int
+			err := applyEach([]int{3, 5, 7}, func(id int) error {
+				if id == tt.failing {
+					return errors.New("entry not found")
+				}
+				applied = append(applied, id)
+				return nil
+			})
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("applyEach error = %q, want %q", gotErr, tt.wantErr)
+			}
+			if len(applied) != len(tt.want) {
+				t.Errorf("applyEach applied %v, want %v", applied, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package paste
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// wtypeModifiers maps chord modifier names to wtype modifier names
+var wtypeModifiers = map[string]string{
+	"ctrl":    "ctrl",
+	"control": "ctrl",
+	"shift":   "shift",
+	"alt":     "alt",
+	"super":   "logo",
+	"logo":    "logo",
+	"win":     "logo",
+	"altgr":   "altgr",
+}
+
+// linuxKeyCodes maps chord key names to Linux input event codes used by ydotool
+var linuxKeyCodes = map[string]int{
+	"ctrl": 29, "control": 29, "shift": 42, "alt": 56, "super": 125, "logo": 125, "win": 125, "altgr": 100,
+	"insert": 110, "enter": 28, "return": 28, "tab": 15, "space": 57,
+	"1": 2, "2": 3, "3": 4, "4": 5, "5": 6, "6": 7, "7": 8, "8": 9, "9": 10, "0": 11,
+	"q": 16, "w": 17, "e": 18, "r": 19, "t": 20, "y": 21, "u": 22, "i": 23, "o": 24, "p": 25,
+	"a": 30, "s": 31, "d": 32, "f": 33, "g": 34, "h": 35, "j": 36, "k": 37, "l": 38,
+	"z": 44, "x": 45, "c": 46, "v": 47, "b": 48, "n": 49, "m": 50,
+}
+
+// parseChord splits a key chord such as "ctrl+shift+v" into modifiers and the key.
+func parseChord(chord string) ([]string, string, error) {
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(chord, " ", "")), "+")
+	key := parts[len(parts)-1]
+	if key == "" {
+		return nil, "", fmt.Errorf("invalid paste_key: %q", chord)
+	}
+	return parts[:len(parts)-1], key, nil
+}
+
+// wtypeChordArgs returns wtype arguments that press and release a key chord.
+func wtypeChordArgs(chord string) ([]string, error) {
+	modifiers, key, err := parseChord(chord)
+	if err != nil {
+		return nil, err
+	}
+
+	var press, release []string
+	for _, m := range modifiers {
+		name, ok := wtypeModifiers[m]
+		if !ok {
+			return nil, fmt.Errorf("unknown modifier in paste_key: %s", m)
+		}
+		press = append(press, "-M", name)
+		release = append([]string{"-m", name}, release...)
+	}
+
+	// wtype takes XKB key names, which are capitalized for named keys (Insert, Return)
+	if len(key) > 1 {
+		key = strings.ToUpper(key[:1]) + key[1:]
+	}
+	args := append(press, "-k", key)
+	return append(args, release...), nil
+}
+
+// ydotoolChordArgs returns ydotool key arguments (code:state) that press and release a key chord.
+func ydotoolChordArgs(chord string) ([]string, error) {
+	modifiers, key, err := parseChord(chord)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []int
+	for _, name := range append(modifiers, key) {
+		code, ok := linuxKeyCodes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown key in paste_key for ydotool: %s", name)
+		}
+		codes = append(codes, code)
+	}
+
+	var args []string
+	for _, code := range codes {
+		args = append(args, strconv.Itoa(code)+":1")
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		args = append(args, strconv.Itoa(codes[i])+":0")
+	}
+	return args, nil
+}
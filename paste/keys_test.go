@@ -0,0 +1,92 @@
+package paste
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		chord     string
+		modifiers []string
+		key       string
+		wantErr   bool
+	}{
+		{"ctrl+v", []string{"ctrl"}, "v", false},
+		{"Ctrl + Shift + V", []string{"ctrl", "shift"}, "v", false},
+		{"shift+Insert", []string{"shift"}, "insert", false},
+		{"v", []string{}, "v", false},
+		{"", nil, "", true},
+		{"ctrl+", nil, "", true},
+		{"ctrl++", nil, "", true},
+	}
+	for _, tt := range tests {
+		modifiers, key, err := parseChord(tt.chord)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseChord(%q) error = %v, want error %v", tt.chord, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (!slices.Equal(modifiers, tt.modifiers) || key != tt.key) {
+			t.Errorf("parseChord(%q) = %q, %q, want %q, %q", tt.chord, modifiers, key, tt.modifiers, tt.key)
+		}
+	}
+}
+
+func TestWtypeChordArgs(t *testing.T) {
+	tests := []struct {
+		chord   string
+		want    string
+		wantErr string
+	}{
+		{"ctrl+v", "-M ctrl -k v -m ctrl", ""},
+		{"ctrl+shift+v", "-M ctrl -M shift -k v -m shift -m ctrl", ""},
+		{"shift+insert", "-M shift -k Insert -m shift", ""},
+		{"super+v", "-M logo -k v -m logo", ""},
+		{"hyper+v", "", "unknown modifier"},
+		{"+v", "", "unknown modifier"},
+		{"ctrl+", "", "invalid paste_key"},
+	}
+	for _, tt := range tests {
+		args, err := wtypeChordArgs(tt.chord)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("wtypeChordArgs(%q) error = %v, want %q", tt.chord, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(args, " ") != tt.want {
+			t.Errorf("wtypeChordArgs(%q) = %q, %v, want %q", tt.chord, args, err, tt.want)
+		}
+	}
+}
+
+func TestYdotoolChordArgs(t *testing.T) {
+	tests := []struct {
+		chord   string
+		want    string
+		wantErr string
+	}{
+		{"ctrl+v", "29:1 47:1 47:0 29:0", ""},
+		{"ctrl+shift+v", "29:1 42:1 47:1 47:0 42:0 29:0", ""},
+		{"shift+insert", "42:1 110:1 110:0 42:0", ""},
+		{"ctrl+f13", "", "unknown key"},
+		{"hyper+v", "", "unknown key"},
+		{"", "", "invalid paste_key"},
+	}
+	for _, tt := range tests {
+		args, err := ydotoolChordArgs(tt.chord)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ydotoolChordArgs(%q) error = %v, want %q", tt.chord, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || strings.Join(args, " ") != tt.want {
+			t.Errorf("ydotoolChordArgs(%q) = %q, %v, want %q", tt.chord, args, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package paste
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"clipbox/config"
+	"clipbox/image"
+)
+
+// Typing backends
+const (
+	BackendWtype   = "wtype"
+	BackendYdotool = "ydotool"
+	BackendCustom  = "custom"
+)
+
+// Paste modes
+const (
+	ModeKey  = "key"  // Send the paste key chord, the entry is already on the clipboard
+	ModeType = "type" // Type the entry text
+)
+
+// DeferredCommand is the hidden clipbox command started by Schedule
+const DeferredCommand = "--paste-deferred"
+
+// CheckTypeable returns an error if content cannot be typed as text.
+func CheckTypeable(content []byte) error {
+	if _, isImage := image.DetectImageFormat(content); isImage {
+		return errors.New("refusing to type an image entry")
+	}
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) != -1 {
+		return errors.New("refusing to type a binary entry")
+	}
+	return nil
+}
+
+// Schedule starts a detached clipbox process that pastes content after paste_delay.
+// The launcher has to close and give focus back to the target window first,
+// so the paste cannot happen in the current process.
+func Schedule(content []byte, cfg *config.Config) error {
+	if cfg.PasteMode == ModeType {
+		if err := CheckTypeable(content); err != nil {
+			return err
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find clipbox executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, DeferredCommand)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	// Otherwise the process would handle the rofi key that started it again
+	cmd.Env = slices.DeleteFunc(os.Environ(), func(env string) bool {
+		return strings.HasPrefix(env, "ROFI_")
+	})
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start paste process: %w", err)
+	}
+
+	// The deferred process reads all input before it waits, so this doesn't block for long
+	if _, err := stdin.Write(content); err != nil {
+		stdin.Close()
+		return fmt.Errorf("failed to pass content to paste process: %w", err)
+	}
+	if err := stdin.Close(); err != nil {
+		return fmt.Errorf("failed to pass content to paste process: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// RunDeferred waits for paste_delay and pastes content. It is run by the process started by Schedule.
+func RunDeferred(content []byte, cfg *config.Config) error {
+	time.Sleep(time.Duration(cfg.PasteDelay) * time.Millisecond)
+	return Run(content, cfg)
+}
+
+// Run sends content or the paste key chord to the focused window through the configured backend.
+func Run(content []byte, cfg *config.Config) error {
+	if cfg.PasteMode == ModeType {
+		if err := CheckTypeable(content); err != nil {
+			return err
+		}
+	}
+
+	var cmd *exec.Cmd
+	switch cfg.PasteBackend {
+	case BackendWtype:
+		if cfg.PasteMode == ModeType {
+			cmd = exec.Command("wtype", "-")
+		} else {
+			args, err := wtypeChordArgs(cfg.PasteKey)
+			if err != nil {
+				return err
+			}
+			cmd = exec.Command("wtype", args...)
+		}
+	case BackendYdotool:
+		if cfg.PasteMode == ModeType {
+			cmd = exec.Command("ydotool", "type", "--file", "-")
+		} else {
+			args, err := ydotoolChordArgs(cfg.PasteKey)
+			if err != nil {
+				return err
+			}
+			cmd = exec.Command("ydotool", append([]string{"key"}, args...)...)
+		}
+	case BackendCustom:
+		if cfg.PasteCommand == "" {
+			return errors.New("paste_backend is custom but paste_command is not set")
+		}
+		cmd = exec.Command("sh", "-c", cfg.PasteCommand)
+		cmd.Env = append(os.Environ(),
+			"CLIPBOX_PASTE_MODE="+cfg.PasteMode,
+			"CLIPBOX_PASTE_KEY="+cfg.PasteKey,
+		)
+	default:
+		return fmt.Errorf("unknown paste_backend: %s", cfg.PasteBackend)
+	}
+
+	cmd.Stdin = bytes.NewReader(content)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to paste: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package paste
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// encodeImage returns a 2x2 image in the format of encode
+func encodeImage(t *testing.T, encode func(*bytes.Buffer, image.Image) error) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCheckTypeable(t *testing.T) {
+	pngImage := encodeImage(t, func(w *bytes.Buffer, m image.Image) error { return png.Encode(w, m) })
+	jpegImage := encodeImage(t, func(w *bytes.Buffer, m image.Image) error { return jpeg.Encode(w, m, nil) })
+	gifImage := encodeImage(t, func(w *bytes.Buffer, m image.Image) error { return gif.Encode(w, m, nil) })
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"text", "hello world", ""},
+		{"unicode", "naïve café ✓\nsecond line", ""},
+		{"png", pngImage, "image"},
+		{"jpeg", jpegImage, "image"},
+		{"gif", gifImage, "image"},
+		{"invalid utf-8", "abc\xff\xfedef", "binary"},
+		{"nul byte", "abc\x00def", "binary"},
+		{"zip", "PK\x03\x04\x14\x00\x00\x00", "binary"},
+	}
+	for _, tt := range tests {
+		err := CheckTypeable([]byte(tt.content))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("CheckTypeable(%s) = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("CheckTypeable(%s) = %v, want a refusal for %s", tt.name, err, tt.wantErr)
+		}
+	}
+}
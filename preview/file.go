@@ -0,0 +1,76 @@
+package preview
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"clipbox/config"
+	"clipbox/utils"
+)
+
+// fileText returns the preview of a file list: the file names, their total size and missing files.
+// Missing files are struck through.
+func fileText(files []string, cfg *config.Config) string {
+	names := make([]string, len(files))
+	var size int64
+	missing := 0
+	for i, path := range files {
+		names[i] = filepath.Base(path)
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			missing++
+		case info.IsDir():
+			names[i] += "/"
+		default:
+			size += info.Size()
+		}
+	}
+
+	text := utils.PangoReplacer.Replace(utils.Trunc(strings.Join(names, ", "), cfg.PreviewWidth, "…"))
+	if len(files) > 1 {
+		text = fmt.Sprintf("%d files: %s", len(files), text)
+	}
+	if missing == len(files) {
+		text = "<s>" + text + "</s>"
+	}
+
+	var details []string
+	if size > 0 {
+		details = append(details, utils.FormatSize(int(size)))
+	}
+	switch {
+	case missing == 0:
+	case len(files) == 1:
+		details = append(details, "missing")
+	default:
+		details = append(details, fmt.Sprintf("%d missing", missing))
+	}
+	if len(details) > 0 {
+		text += " • " + strings.Join(details, " • ")
+	}
+	return text
+}
+
+// fileIcon returns the theme icon of a single file by its mime type, or the file type icon.
+func fileIcon(files []string, cfg *config.Config) string {
+	if len(files) == 1 {
+		if info, err := os.Stat(files[0]); err == nil {
+			if info.IsDir() {
+				return "folder"
+			}
+			if mimeType := mime.TypeByExtension(filepath.Ext(files[0])); mimeType != "" {
+				// Icon names replace the slash of the mime type, as in application-pdf
+				mimeType, _, _ = strings.Cut(mimeType, ";")
+				return strings.ReplaceAll(mimeType, "/", "-")
+			}
+		}
+	}
+	return cfg.TypeIcons["file"]
+}
@@ -0,0 +1,84 @@
+package preview
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"clipbox/config"
+)
+
+func TestFileText(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notes, make([]byte, 2048), 0644); err != nil {
+		t.Fatal(err)
+	}
+	photo := filepath.Join(dir, "a&b.png")
+	if err := os.WriteFile(photo, make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	folder := filepath.Join(dir, "folder")
+	if err := os.Mkdir(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+	cfg := &config.Config{PreviewWidth: 65}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"single file", []string{notes}, "notes.txt • 2.00 KiB"},
+		{"escaped name", []string{photo}, "a&amp;b.png • 100 B"},
+		{"directory", []string{folder}, "folder/"},
+		{"several files", []string{notes, photo, folder}, "3 files: notes.txt, a&amp;b.png, folder/ • 2.10 KiB"},
+		{"missing file", []string{missing}, "<s>missing.txt</s> • missing"},
+		{"some missing", []string{notes, missing, missing}, "3 files: notes.txt, missing.txt, missing.txt • 2.00 KiB • 2 missing"},
+		{"all missing", []string{missing, missing}, "<s>2 files: missing.txt, missing.txt</s> • 2 missing"},
+	}
+	for _, tt := range tests {
+		if got := fileText(tt.files, cfg); got != tt.want {
+			t.Errorf("%s: fileText = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Long lists of names are truncated to preview_width
+	cfg.PreviewWidth = 12
+	if got, want := fileText([]string{notes, notes}, cfg), "2 files: notes.txt, n… • 4.00 KiB"; got != want {
+		t.Errorf("fileText with preview_width=12 = %q, want %q", got, want)
+	}
+}
+
+func TestFileIcon(t *testing.T) {
+	dir := t.TempDir()
+	pdf := filepath.Join(dir, "report.pdf")
+	unknown := filepath.Join(dir, "data.clipbox-unknown")
+	for _, path := range []string{pdf, unknown} {
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{TypeIcons: map[string]string{"file": "text-x-generic"}}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"pdf", []string{pdf}, "application-pdf"},
+		{"directory", []string{dir}, "folder"},
+		{"unknown extension", []string{unknown}, "text-x-generic"},
+		{"missing file", []string{filepath.Join(dir, "missing.pdf")}, "text-x-generic"},
+		{"several files", []string{pdf, dir}, "text-x-generic"},
+	}
+	for _, tt := range tests {
+		if got := fileIcon(tt.files, cfg); got != tt.want {
+			t.Errorf("%s: fileIcon = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package preview
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"clipbox/config"
+)
+
+// formatVersion changes whenever stored previews or content types change for the same settings,
+// so entries stored by older versions are regenerated
+const formatVersion = 15
+
+// fingerprintFields are the settings used by GeneratePreview
+type fingerprintFields struct {
+	PinnedMarker           string
+	UnpinnedMarker         string
+	ClipboardMarker        string
+	PrimaryMarker          string
+	TemplateMarker         string
+	ExpandTemplates        bool
+	ShowSourceApp          bool
+	ShowImageIcons         bool
+	ShowTypeBadges         bool
+	TypeBadges             map[string]string
+	TypeBadgeColors        map[string]string
+	ShowTypeIcons          bool
+	TypeIcons              map[string]string
+	PreviewWidth           int
+	MaskPasswords          int
+	PasswordMaskColor      string
+	PasswordMaskChar       string
+	PasswordIgnorePatterns []string
+	SecretThreshold        float64
+}
+
+// localZone identifies the local time zone used for dates in previews by its winter and summer offsets
+func localZone() string {
+	winter, winterOffset := time.Date(2000, 1, 1, 0, 0, 0, 0, time.Local).Zone()
+	summer, summerOffset := time.Date(2000, 7, 1, 0, 0, 0, 0, time.Local).Zone()
+	return fmt.Sprintf("%s%d/%s%d", winter, winterOffset, summer, summerOffset)
+}
+
+// Fingerprint returns a hash of all settings affecting previews, including [buffer.N] sections
+// and the local time zone.
+// Stored previews are stale when it differs from the fingerprint they were generated with.
+func Fingerprint(cfg *config.Config) string {
+	buffers := make([]fingerprintFields, 5)
+	for i := range buffers {
+		c := cfg.ForBuffer(i + 1)
+		buffers[i] = fingerprintFields{
+			PinnedMarker:           c.PinnedMarker,
+			UnpinnedMarker:         c.UnpinnedMarker,
+			ClipboardMarker:        c.ClipboardMarker,
+			PrimaryMarker:          c.PrimaryMarker,
+			TemplateMarker:         c.TemplateMarker,
+			ExpandTemplates:        c.ExpandTemplates,
+			ShowSourceApp:          c.ShowSourceApp,
+			ShowImageIcons:         c.ShowImageIcons,
+			ShowTypeBadges:         c.ShowTypeBadges,
+			TypeBadges:             c.TypeBadges,
+			TypeBadgeColors:        c.TypeBadgeColors,
+			ShowTypeIcons:          c.ShowTypeIcons,
+			TypeIcons:              c.TypeIcons,
+			PreviewWidth:           c.PreviewWidth,
+			MaskPasswords:          c.MaskPasswords,
+			PasswordMaskColor:      c.PasswordMaskColor,
+			PasswordMaskChar:       c.PasswordMaskChar,
+			PasswordIgnorePatterns: c.PasswordIgnorePatterns,
+			SecretThreshold:        c.SecretThreshold,
+		}
+	}
+
+	data, _ := json.Marshal(struct {
+		Version   int
+		Buffers   []fingerprintFields
+		LocalZone string
+	}{formatVersion, buffers, localZone()})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -9,13 +9,14 @@ import (
 	"image"
 	"io"
 	"strings"
-	"unicode/utf8"
+	"time"
 
 	_ "image/gif"
 	_ "image/jpeg"
 
 	"clipbox/config"
 	"clipbox/detect"
+	"clipbox/snippet"
 	"clipbox/utils"
 )
 
@@ -25,14 +26,36 @@ const (
 	firstCharsCount = 2
 	lastCharsCount  = 4
 	maxImageSize    = 1024 * 1024
+
+	maxSourceAppLength = 16
 )
 
+// LocalTimeLayout formats the local time of detected dates
+const LocalTimeLayout = "2006-01-02 15:04 MST"
+
 const (
 	passwordMaskText = "[[ PASSWORD ]]"
 	rofiIconSep      = "\x00icon\x1f"
 	rofiInfoSep      = "\x00info\x1f"
 )
 
+// credentialLabels are the placeholders of multi-line credentials by kind
+var credentialLabels = map[string]string{
+	detect.CredentialPrivateKey:    "[[ PRIVATE KEY ]]",
+	detect.CredentialSSHPrivateKey: "[[ SSH PRIVATE KEY ]]",
+	detect.CredentialPGPPrivateKey: "[[ PGP PRIVATE KEY ]]",
+	detect.CredentialEnv:           "[[ ENV CREDENTIALS ]]",
+	detect.CredentialKubeconfig:    "[[ KUBECONFIG ]]",
+}
+
+// CredentialLabel returns the placeholder shown instead of a credential detected by detect.DetectCredential.
+func CredentialLabel(kind string) string {
+	if label, ok := credentialLabels[kind]; ok {
+		return label
+	}
+	return "[[ CREDENTIALS ]]"
+}
+
 // MaskPassword masks a password based on the masking mode.
 // Mode 1: Shows first 2 and last 4 characters, masks middle with specified color and character.
 // Mode 2: Fully masks password as [[ PASSWORD ]]
@@ -68,23 +91,82 @@ func MaskPassword(password string, mode int, maskColor string, maskChar string)
 		firstPartEscaped, maskColorEscaped, maskedPartEscaped, lastPartEscaped)
 }
 
+// Entry holds the stored fields of a clipboard entry needed to render its preview.
+type Entry struct {
+	ID        int
+	Buffer    int // Selects the [buffer.N] config section, 0 uses the global settings
+	Content   []byte
+	IsPinned  int
+	HasIcon   bool
+	IconPath  string
+	Source    string // utils.SourceClipboard or utils.SourcePrimary
+	SourceApp string // Id of the application the entry was copied from
+	// TemplateDisabled turns off placeholder expansion for this entry
+	TemplateDisabled bool
+}
+
 // GeneratePreview creates a complete rofi display line with marker, preview text, and metadata.
-func GeneratePreview(id int, content []byte, isPinned int, hasIcon bool, iconPath string, cfg *config.Config) string {
-	previewText := generatePreviewText(content, cfg)
-	marker := getMarker(isPinned, cfg)
-	preview := marker + " " + previewText
+func GeneratePreview(entry Entry, cfg *config.Config) string {
+	return GeneratePreviewClassified(entry, classify(entry.Content, cfg.ForBuffer(entry.Buffer)), cfg)
+}
+
+// GeneratePreviewClassified is GeneratePreview for content that was already classified
+// with the settings of its buffer.
+func GeneratePreviewClassified(entry Entry, classification detect.Classification, cfg *config.Config) string {
+	cfg = cfg.ForBuffer(entry.Buffer)
+	previewText := generatePreviewText(entry.Content, classification, cfg)
+	marker := getMarker(entry.IsPinned, cfg)
+	if sourceMarker := getSourceMarker(entry.Source, cfg); sourceMarker != "" {
+		marker += " " + sourceMarker
+	}
+	if cfg.ExpandTemplates && !entry.TemplateDisabled && cfg.TemplateMarker != "" && snippet.HasPlaceholders(entry.Content) {
+		marker += " " + cfg.TemplateMarker
+	}
+	if cfg.ShowSourceApp && entry.SourceApp != "" {
+		app := utils.Trunc(entry.SourceApp, maxSourceAppLength, "…")
+		marker += " [" + utils.PangoReplacer.Replace(app) + "]"
+	}
+	if badge := typeBadge(classification, cfg); badge != "" {
+		marker += " " + badge
+	}
+	// The hidden ID survives in the row text returned by rofi -dmenu
+	preview := marker + " " + previewText + utils.EncodeIDHidden(entry.ID)
 
-	if hasIcon && iconPath != "" {
-		hiddenID := utils.EncodeIDHidden(id)
-		iconMeta := rofiIconSep + iconPath
-		return fmt.Sprintf("%s%s%s%s%d", preview, hiddenID, iconMeta, rofiInfoSep, id)
+	if entry.HasIcon && entry.IconPath != "" {
+		iconMeta := rofiIconSep + entry.IconPath
+		return fmt.Sprintf("%s%s%s%d", preview, iconMeta, rofiInfoSep, entry.ID)
 	}
+	if icon := typeIcon(classification, cfg); icon != "" {
+		return fmt.Sprintf("%s%s%s%s%d", preview, rofiIconSep, icon, rofiInfoSep, entry.ID)
+	}
+
+	return fmt.Sprintf("%s%s%d", preview, rofiInfoSep, entry.ID)
+}
 
-	return fmt.Sprintf("%s%s%d", preview, rofiInfoSep, id)
+// Text returns the preview text of an entry as Pango markup, without markers.
+func Text(entry Entry, cfg *config.Config) string {
+	cfg = cfg.ForBuffer(entry.Buffer)
+	return generatePreviewText(entry.Content, classify(entry.Content, cfg), cfg)
+}
+
+// ParseLine splits a stored preview line into the Pango markup shown in the list and the icon.
+// The hidden ID and rofi metadata are removed.
+func ParseLine(line string, id int) (markup string, icon string) {
+	markup, meta, _ := strings.Cut(line, "\x00")
+	markup = strings.TrimSuffix(markup, utils.EncodeIDHidden(id))
+
+	// Metadata is a sequence of key\x1fvalue pairs, each one may start with \x00
+	fields := strings.Split(strings.ReplaceAll(meta, "\x00", "\x1f"), "\x1f")
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "icon" {
+			icon = fields[i+1]
+		}
+	}
+	return markup, icon
 }
 
 // generatePreviewText generates the preview text based on content type.
-func generatePreviewText(content []byte, cfg *config.Config) string {
+func generatePreviewText(content []byte, classification detect.Classification, cfg *config.Config) string {
 	// Try to decode as image first
 	limitedReader := io.LimitReader(bytes.NewReader(content), maxImageSize)
 	if imgConfig, format, err := image.DecodeConfig(limitedReader); err == nil {
@@ -94,30 +176,108 @@ func generatePreviewText(content []byte, cfg *config.Config) string {
 	}
 
 	// Check if binary content
-	if !utf8.Valid(content) {
-		return fmt.Sprintf("[[ BIN: %s ]]", utils.FormatSize(len(content)))
+	if classification.Type == detect.TypeBinary {
+		name := classification.Format.Name
+		if name == "" {
+			name = "BIN"
+		}
+		return fmt.Sprintf("[[ %s: %s ]]", utils.PangoReplacer.Replace(name), utils.FormatSize(len(content)))
+	}
+
+	// UTF-16 and legacy encodings are shown as their decoded text, with LF line endings
+	content = []byte(utils.NormalizeLineEndings(string(detect.TextContent(content))))
+
+	// Files copied in a file manager
+	if classification.Type == detect.TypeFile {
+		return fileText(classification.Files, cfg)
 	}
 
 	// Process text content
-	return processTextContent(content, cfg)
+	return processTextContent(content, classification, cfg)
 }
 
 // processTextContent processes text content, applying password masking if needed.
-func processTextContent(content []byte, cfg *config.Config) string {
+func processTextContent(content []byte, classification detect.Classification, cfg *config.Config) string {
 	text := strings.TrimSpace(string(content))
 
+	// Private keys and credential blocks are masked whatever the masking mode
+	if classification.Type == detect.TypeCredential {
+		return CredentialLabel(classification.SecretKind)
+	}
+
 	// Check if content is a password and masking is enabled
-	if cfg.MaskPasswords > 0 && detect.IsPassword(content, cfg.PasswordIgnorePatterns) {
+	if cfg.MaskPasswords > 0 && classification.Type == detect.TypePassword {
 		text = utils.Trunc(text, cfg.PreviewWidth, "…")
 		return MaskPassword(text, cfg.MaskPasswords, cfg.PasswordMaskColor, cfg.PasswordMaskChar)
 	}
 
+	// Structured data and code are summarized instead of collapsed into a line
+	if summary, ok := summaryText(content, classification, cfg); ok {
+		return summary
+	}
+
 	// Normal text processing
 	text = strings.Join(strings.Fields(text), " ")
+
+	// Timestamps and dates in other time zones are followed by their local time
+	if classification.Type == detect.TypeDateTime {
+		if t, ok := detect.ParseDateTime(content); ok && t.Location() != time.Local {
+			text += " → " + t.Local().Format(LocalTimeLayout)
+		}
+	}
 	text = utils.Trunc(text, cfg.PreviewWidth, "…")
 	return utils.PangoReplacer.Replace(text)
 }
 
+// classify classifies content with the secret detection settings of cfg
+func classify(content []byte, cfg *config.Config) detect.Classification {
+	return detect.NewClassifier(cfg.PasswordIgnorePatterns, cfg.SecretThreshold).Classify(content)
+}
+
+// typeKey returns the key of config.TypeKeys for a classification, or "" for images and binary data.
+// Passwords are plain text while mask_passwords is 0, as they are shown unmasked.
+func typeKey(classification detect.Classification, cfg *config.Config) string {
+	switch classification.Type {
+	case detect.TypeImage, detect.TypeBinary:
+		return ""
+	case detect.TypePassword:
+		if cfg.MaskPasswords == 0 {
+			return "text"
+		}
+		return "secret"
+	case detect.TypeCredential:
+		return "secret"
+	}
+	return classification.Type
+}
+
+// typeBadge returns the colored badge of the content type, or "" if there is none.
+func typeBadge(classification detect.Classification, cfg *config.Config) string {
+	if !cfg.ShowTypeBadges {
+		return ""
+	}
+	badge := cfg.TypeBadges[typeKey(classification, cfg)]
+	if badge == "" {
+		return ""
+	}
+	badge = utils.PangoReplacer.Replace(badge)
+	if color := cfg.TypeBadgeColors[typeKey(classification, cfg)]; color != "" {
+		return fmt.Sprintf("<span color='%s' size='small'>%s</span>", utils.PangoReplacer.Replace(color), badge)
+	}
+	return badge
+}
+
+// typeIcon returns the theme icon name of the content type, or "" if there is none.
+func typeIcon(classification detect.Classification, cfg *config.Config) string {
+	if !cfg.ShowTypeIcons {
+		return ""
+	}
+	if classification.Type == detect.TypeFile {
+		return fileIcon(classification.Files, cfg)
+	}
+	return cfg.TypeIcons[typeKey(classification, cfg)]
+}
+
 // getMarker returns the appropriate marker based on pinned status.
 func getMarker(isPinned int, cfg *config.Config) string {
 	if isPinned == 1 {
@@ -125,3 +285,11 @@ func getMarker(isPinned int, cfg *config.Config) string {
 	}
 	return cfg.UnpinnedMarker
 }
+
+// getSourceMarker returns the marker for the selection an entry was captured from.
+func getSourceMarker(source string, cfg *config.Config) string {
+	if source == utils.SourcePrimary {
+		return cfg.PrimaryMarker
+	}
+	return cfg.ClipboardMarker
+}
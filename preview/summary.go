@@ -0,0 +1,134 @@
+package preview
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"clipbox/config"
+	"clipbox/detect"
+	"clipbox/utils"
+)
+
+// htmlTitleRegex matches the title of an HTML document
+var htmlTitleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// commentPrefixes start lines that are skipped when looking for the first meaningful line of code
+var commentPrefixes = []string{"#", "//", "/*", "*", "--", "<!--", "<?php"}
+
+// Summary returns a one-line description of the structure of JSON, YAML, XML and HTML content and code,
+// such as "JSON object • 12 keys • 3.40 KiB". Returns "" for other types.
+func Summary(content []byte, classification detect.Classification) string {
+	size := utils.FormatSize(len(content))
+	switch classification.Type {
+	case detect.TypeJSON:
+		var value any
+		if err := json.Unmarshal(content, &value); err != nil {
+			return ""
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			return fmt.Sprintf("JSON object • %s • %s", count(len(v), "key"), size)
+		case []any:
+			return fmt.Sprintf("JSON array • %s • %s", count(len(v), "item"), size)
+		}
+	case detect.TypeYAML:
+		root, err := detect.ParseYAML(content)
+		if err != nil || root == nil {
+			return ""
+		}
+		switch root.Kind {
+		case yaml.MappingNode:
+			return fmt.Sprintf("YAML mapping • %s • %s", count(len(root.Content)/2, "key"), size)
+		case yaml.SequenceNode:
+			return fmt.Sprintf("YAML list • %s • %s", count(len(root.Content), "item"), size)
+		}
+	case detect.TypeXML:
+		root, elements, err := detect.ParseXML(content)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("XML <%s> • %s • %s", root, count(elements, "element"), size)
+	case detect.TypeHTML:
+		if match := htmlTitleRegex.FindSubmatch(content); match != nil {
+			if title := strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " "); title != "" {
+				return fmt.Sprintf("HTML • %s • %s", title, size)
+			}
+		}
+		return "HTML document • " + size
+	case detect.TypeCode:
+		lines := strings.Count(strings.TrimSpace(string(content)), "\n") + 1
+		if classification.Language != "" {
+			return fmt.Sprintf("%s code • %s • %s", classification.Language, count(lines, "line"), size)
+		}
+		return fmt.Sprintf("Code • %s • %s", count(lines, "line"), size)
+	}
+	return ""
+}
+
+// summaryText returns the preview of structured content and code, or false for other types.
+// Short JSON is shown compacted, code by its first meaningful line.
+func summaryText(content []byte, classification detect.Classification, cfg *config.Config) (string, bool) {
+	switch classification.Type {
+	case detect.TypeJSON:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, bytes.TrimSpace(content)); err == nil && utf8.RuneCount(compact.Bytes()) <= cfg.PreviewWidth {
+			return utils.PangoReplacer.Replace(compact.String()), true
+		}
+	case detect.TypeCode:
+		line := firstCodeLine(string(content))
+		if line == "" {
+			return "", false
+		}
+		text := utils.PangoReplacer.Replace(utils.Trunc(line, cfg.PreviewWidth, "…"))
+		if lines := strings.Count(strings.TrimSpace(string(content)), "\n") + 1; lines > 1 {
+			text += " • " + count(lines, "line")
+		}
+		return text, true
+	}
+
+	summary := Summary(content, classification)
+	if summary == "" {
+		return "", false
+	}
+	return utils.PangoReplacer.Replace(utils.Trunc(summary, cfg.PreviewWidth, "…")), true
+}
+
+// firstCodeLine returns the first line of code that is not blank, a comment or a shebang, with collapsed spaces
+func firstCodeLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" || hasAnyPrefix(line, commentPrefixes) {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
+// hasAnyPrefix reports whether text starts with one of prefixes
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// count formats a number with a noun in singular or plural, such as "1 key" or "12 keys"
+func count(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,103 @@
+package main
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+
+	"clipbox/transform"
+	"clipbox/utils"
+)
+
+// runRofi handles a call from rofi script mode.
+// retv is ROFI_RETV and args holds the selected row, if any.
+func runRofi(b backend, retv int, args []string) error {
+	// The selected row carries the hidden entry ID
+	id := 0
+	if len(args) >= 1 && args[0] != "" {
+		if selected, err := utils.ExtractID(args[0]); err == nil && selected > 0 {
+			id = selected
+		}
+	}
+
+	// Selection in the transform submenu
+	if menuID, ok := transformMenuEntryID(os.Getenv("ROFI_DATA")); ok && retv == 1 {
+		if err := loadTransforms(); err != nil {
+			return err
+		}
+		if _, ok := transform.Get(os.Getenv("ROFI_INFO")); ok {
+			return runTransform(b, os.Getenv("ROFI_INFO"), menuID)
+		}
+	}
+
+	// Value typed into a placeholder prompt
+	if retv == 2 && len(args) >= 1 {
+		handled, err := continueSnippetPrompt(b, os.Getenv("ROFI_DATA"), args[0])
+		if err != nil || handled {
+			return err
+		}
+	}
+
+	switch retv {
+	case 1: // Enter: copy the entry
+		if id > 0 {
+			return selectEntry(b, id, false, url.Values{})
+		}
+	case 10: // kb-custom-1: toggle pin
+		if id > 0 {
+			if err := b.TogglePin(id); err != nil {
+				return err
+			}
+		}
+	case 11, 12, 13, 14, 15: // kb-custom-2 to kb-custom-6: switch buffer
+		if err := b.SwitchBuffer(retv - 10); err != nil {
+			return err
+		}
+	case 16: // kb-custom-7: delete entry
+		if id > 0 {
+			if err := b.Delete(id); err != nil {
+				return err
+			}
+		}
+	case 17: // kb-custom-8: switch to previous buffer
+		if err := b.SwitchToPreviousBuffer(); err != nil {
+			return err
+		}
+	case 18: // kb-custom-9: switch to next buffer
+		if err := b.SwitchToNextBuffer(); err != nil {
+			return err
+		}
+	case 19: // kb-custom-10: open transform submenu
+		if id > 0 {
+			return writeTransformMenu(os.Stdout, b, id)
+		}
+	case 20: // kb-custom-11: copy and paste into the focused window
+		if id > 0 {
+			return selectEntry(b, id, true, url.Values{})
+		}
+	case 21: // kb-custom-12: toggle placeholder expansion
+		if id > 0 {
+			if err := b.ToggleTemplate(id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return listRofi(b, 0, "")
+}
+
+// rofiRetv returns ROFI_RETV and whether clipbox runs in rofi script mode.
+func rofiRetv() (int, bool) {
+	value := os.Getenv("ROFI_RETV")
+	if value == "" {
+		return 0, false
+	}
+	retv, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return retv, true
+}
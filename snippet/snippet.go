@@ -0,0 +1,117 @@
+package snippet
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const defaultDateLayout = "2006-01-02"
+
+// placeholderRegex matches {{date}}, {{date:LAYOUT}}, {{clipboard}}, {{env:NAME}}, {{uuid}} and {{prompt:LABEL}}
+var placeholderRegex = regexp.MustCompile(`\{\{(date(?::[^{}]+)?|clipboard|env:[A-Za-z_][A-Za-z0-9_]*|uuid|prompt:[^{}]+)\}\}`)
+
+// Sources provides the values that depend on the environment.
+// {{env:NAME}} placeholders are left as they are if Env is nil.
+type Sources struct {
+	Now       func() time.Time
+	Clipboard func() ([]byte, error)
+	Env       func(name string) string
+	Prompt    func(label string) (string, error)
+}
+
+// HasPlaceholders reports whether content is text with at least one placeholder.
+func HasPlaceholders(content []byte) bool {
+	return utf8.Valid(content) && placeholderRegex.Match(content)
+}
+
+// PromptLabels returns the labels of {{prompt:...}} placeholders in order of appearance, without duplicates.
+func PromptLabels(content []byte) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, match := range placeholderRegex.FindAllSubmatch(content, -1) {
+		label, ok := strings.CutPrefix(string(match[1]), "prompt:")
+		if ok && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Expand replaces placeholders in content.
+// The clipboard is read once, and each prompt label is asked only once.
+func Expand(content []byte, sources Sources) ([]byte, error) {
+	if !HasPlaceholders(content) {
+		return content, nil
+	}
+
+	now := sources.Now()
+	var clipboard []byte
+	clipboardRead := false
+	answers := make(map[string]string)
+
+	var expandErr error
+	result := placeholderRegex.ReplaceAllFunc(content, func(match []byte) []byte {
+		if expandErr != nil {
+			return nil
+		}
+
+		name := string(match[2 : len(match)-2])
+		kind, arg, _ := strings.Cut(name, ":")
+
+		switch kind {
+		case "date":
+			layout := arg
+			if layout == "" {
+				layout = defaultDateLayout
+			}
+			return []byte(now.Format(layout))
+		case "clipboard":
+			if !clipboardRead {
+				clipboard, expandErr = sources.Clipboard()
+				clipboardRead = true
+			}
+			return clipboard
+		case "env":
+			if sources.Env == nil {
+				return match
+			}
+			return []byte(sources.Env(arg))
+		case "uuid":
+			var uuid string
+			uuid, expandErr = newUUID()
+			return []byte(uuid)
+		case "prompt":
+			answer, ok := answers[arg]
+			if !ok {
+				answer, expandErr = sources.Prompt(arg)
+				answers[arg] = answer
+			}
+			return []byte(answer)
+		}
+		return match
+	})
+
+	if expandErr != nil {
+		return nil, expandErr
+	}
+	return result, nil
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
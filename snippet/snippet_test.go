@@ -0,0 +1,144 @@
+package snippet
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+// testSources returns fixed values and counts clipboard reads and prompts
+func testSources(clipboardReads *int, prompts *[]string) Sources {
+	return Sources{
+		Now: func() time.Time { return time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC) },
+		Clipboard: func() ([]byte, error) {
+			*clipboardReads++
+			return []byte("copied"), nil
+		},
+		Env: func(name string) string {
+			if name == "USER" {
+				return "maxim"
+			}
+			return ""
+		},
+		Prompt: func(label string) (string, error) {
+			*prompts = append(*prompts, label)
+			return "answer to " + label, nil
+		},
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		reads   int
+		prompts []string
+	}{
+		{"no placeholders", "plain {text}", "plain {text}", 0, nil},
+		{"date", "{{date}}", "2025-03-14", 0, nil},
+		{"date layout", "at {{date:15:04}}", "at 15:09", 0, nil},
+		{"clipboard read once", "{{clipboard}} and {{clipboard}}", "copied and copied", 1, nil},
+		{"env", "hi {{env:USER}}", "hi maxim", 0, nil},
+		{"unset env", "[{{env:CLIPBOX_UNSET}}]", "[]", 0, nil},
+		{"prompt asked once", "{{prompt:Name}}, {{prompt:Name}}: {{prompt:Age}}", "answer to Name, answer to Name: answer to Age", 0, []string{"Name", "Age"}},
+		{"unknown placeholder", "{{unknown}} {{env:1BAD}}", "{{unknown}} {{env:1BAD}}", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := 0
+			var prompts []string
+			got, err := Expand([]byte(tt.content), testSources(&reads, &prompts))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+			if reads != tt.reads {
+				t.Errorf("Expand(%q) read the clipboard %d times, want %d", tt.content, reads, tt.reads)
+			}
+			if !slices.Equal(prompts, tt.prompts) {
+				t.Errorf("Expand(%q) prompted %q, want %q", tt.content, prompts, tt.prompts)
+			}
+		})
+	}
+}
+
+func TestExpandWithoutEnv(t *testing.T) {
+	reads := 0
+	var prompts []string
+	sources := testSources(&reads, &prompts)
+	sources.Env = nil
+	got, err := Expand([]byte("token={{env:GITHUB_TOKEN}} on {{date}}"), sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "token={{env:GITHUB_TOKEN}} on 2025-03-14"; string(got) != want {
+		t.Errorf("Expand without Env = %q, want %q", got, want)
+	}
+}
+
+func TestExpandUUID(t *testing.T) {
+	reads := 0
+	var prompts []string
+	got, err := Expand([]byte("{{uuid}} {{uuid}}"), testSources(&reads, &prompts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uuid := `[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`
+	match := regexp.MustCompile(`^(` + uuid + `) (` + uuid + `)$`).FindStringSubmatch(string(got))
+	if match == nil {
+		t.Fatalf("Expand({{uuid}} {{uuid}}) = %q, want two version 4 UUIDs", got)
+	}
+	if match[1] == match[2] {
+		t.Errorf("Expand({{uuid}} {{uuid}}) repeated %s, want a new UUID each time", match[1])
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	reads := 0
+	var prompts []string
+	sources := testSources(&reads, &prompts)
+	sources.Prompt = func(label string) (string, error) { return "", errors.New("prompt cancelled") }
+	if _, err := Expand([]byte("{{prompt:Name}}"), sources); err == nil {
+		t.Error("Expand with a cancelled prompt succeeded, want an error")
+	}
+
+	sources = testSources(&reads, &prompts)
+	sources.Clipboard = func() ([]byte, error) { return nil, errors.New("wl-paste missing") }
+	if _, err := Expand([]byte("{{clipboard}}"), sources); err == nil {
+		t.Error("Expand with a failing clipboard succeeded, want an error")
+	}
+}
+
+func TestHasPlaceholders(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"{{date}}", true},
+		{"Hello {{prompt:Name}}", true},
+		{"{{ date }}", false},
+		{"{date}", false},
+		{"{{date}}\xff", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := HasPlaceholders([]byte(tt.content)); got != tt.want {
+			t.Errorf("HasPlaceholders(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestPromptLabels(t *testing.T) {
+	got := PromptLabels([]byte("{{prompt:B}} {{date}} {{prompt:A}} {{prompt:B}}"))
+	if want := []string{"B", "A"}; !slices.Equal(got, want) {
+		t.Errorf("PromptLabels = %q, want %q", got, want)
+	}
+}
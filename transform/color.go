@@ -0,0 +1,117 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+
+	"clipbox/detect"
+)
+
+// colorTransforms convert colors between CSS and other notations
+var colorTransforms = []Transform{
+	{
+		Name:        "color-hex",
+		Description: "Color as #rrggbb",
+		Match:       detect.IsColor,
+		Apply: colorTransform(func(c color.NRGBA) string {
+			if c.A < 255 {
+				return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+			}
+			return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+		}),
+	},
+	{
+		Name:        "color-rgb",
+		Description: "Color as rgb(r, g, b)",
+		Match:       detect.IsColor,
+		Apply: colorTransform(func(c color.NRGBA) string {
+			if c.A < 255 {
+				return fmt.Sprintf("rgba(%d, %d, %d, %s)", c.R, c.G, c.B, formatAlpha(c.A))
+			}
+			return fmt.Sprintf("rgb(%d, %d, %d)", c.R, c.G, c.B)
+		}),
+	},
+	{
+		Name:        "color-hsl",
+		Description: "Color as hsl(h, s%, l%)",
+		Match:       detect.IsColor,
+		Apply: colorTransform(func(c color.NRGBA) string {
+			h, s, l := rgbToHSL(c)
+			if c.A < 255 {
+				return fmt.Sprintf("hsla(%.0f, %.0f%%, %.0f%%, %s)", h, s*100, l*100, formatAlpha(c.A))
+			}
+			return fmt.Sprintf("hsl(%.0f, %.0f%%, %.0f%%)", h, s*100, l*100)
+		}),
+	},
+	{
+		Name:        "color-float",
+		Description: "Color as 0-1 floats for shaders",
+		Match:       detect.IsColor,
+		Apply: colorTransform(func(c color.NRGBA) string {
+			text := fmt.Sprintf("%.3f, %.3f, %.3f", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+			if c.A < 255 {
+				text += fmt.Sprintf(", %.3f", float64(c.A)/255)
+			}
+			return text
+		}),
+	},
+	{
+		Name:        "color-0x",
+		Description: "Color as 0xRRGGBB",
+		Match:       detect.IsColor,
+		Apply: colorTransform(func(c color.NRGBA) string {
+			if c.A < 255 {
+				return fmt.Sprintf("0x%02X%02X%02X%02X", c.R, c.G, c.B, c.A)
+			}
+			return fmt.Sprintf("0x%02X%02X%02X", c.R, c.G, c.B)
+		}),
+	},
+}
+
+// colorTransform returns an Apply function that parses a color and formats it
+func colorTransform(format func(c color.NRGBA) string) func(content []byte) ([]byte, error) {
+	return func(content []byte) ([]byte, error) {
+		c, ok := detect.ParseColor(content)
+		if !ok {
+			return nil, fmt.Errorf("not a color")
+		}
+		return []byte(format(c)), nil
+	}
+}
+
+// formatAlpha formats an alpha byte as a number from 0 to 1 with at most two decimals
+func formatAlpha(a uint8) string {
+	return strconv.FormatFloat(math.Round(float64(a)/255*100)/100, 'f', -1, 64)
+}
+
+// rgbToHSL returns the hue in degrees and saturation and lightness from 0 to 1
+func rgbToHSL(c color.NRGBA) (float64, float64, float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxC, minC := max(r, g, b), min(r, g, b)
+	l := (maxC + minC) / 2
+	if maxC == minC {
+		return 0, 0, l
+	}
+
+	d := maxC - minC
+	s := d / (2 - maxC - minC)
+	if l < 0.5 {
+		s = d / (maxC + minC)
+	}
+
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return math.Mod(h*60, 360), s, l
+}
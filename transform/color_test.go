@@ -0,0 +1,75 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestColorTransforms(t *testing.T) {
+	tests := []struct {
+		transform string
+		text      string
+		want      string
+	}{
+		{"color-hex", "rgb(30, 144, 255)", "#1e90ff"},
+		{"color-hex", "rgba(255, 0, 0, 0.5)", "#ff000080"},
+		{"color-rgb", "#1E90FF", "rgb(30, 144, 255)"},
+		{"color-rgb", "#ff000080", "rgba(255, 0, 0, 0.5)"},
+		{"color-hsl", "#1e90ff", "hsl(210, 100%, 56%)"},
+		{"color-hsl", "hsl(0 100% 50% / 50%)", "hsla(0, 100%, 50%, 0.5)"},
+		{"color-float", "#ff8000", "1.000, 0.502, 0.000"},
+		{"color-float", "#ff000080", "1.000, 0.000, 0.000, 0.502"},
+		{"color-0x", "#1e90ff", "0x1E90FF"},
+		{"color-0x", "#1e90ff80", "0x1E90FF80"},
+	}
+	for _, tt := range tests {
+		got, err := Apply(tt.transform, []byte(tt.text))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s of %q = %q, %v, want %q", tt.transform, tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestColorTransformsMatch(t *testing.T) {
+	hex, ok := Get("color-hex")
+	if !ok {
+		t.Fatal("color-hex is not registered")
+	}
+	for _, text := range []string{"#123", "#1234", "#add", "red", "rgb(300, 0, 0)"} {
+		if hex.Match([]byte(text)) {
+			t.Errorf("color-hex matches %q, want no match", text)
+		}
+	}
+	if _, err := Apply("color-hex", []byte("#add")); err == nil {
+		t.Error("color-hex of #add succeeded, want an error")
+	}
+}
+
+func TestRGBToHSL(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       color.NRGBA
+		h, s, l float64
+	}{
+		{"red", color.NRGBA{255, 0, 0, 255}, 0, 1, 0.5},
+		{"green", color.NRGBA{0, 255, 0, 255}, 120, 1, 0.5},
+		{"blue", color.NRGBA{0, 0, 255, 255}, 240, 1, 0.5},
+		{"magenta", color.NRGBA{255, 0, 255, 255}, 300, 1, 0.5},
+		{"dark red", color.NRGBA{128, 0, 0, 255}, 0, 1, 0.251},
+		{"pale blue", color.NRGBA{30, 144, 255, 255}, 209.6, 1, 0.559},
+		{"muted orange", color.NRGBA{200, 150, 100, 255}, 30, 0.476, 0.588},
+		{"white", color.NRGBA{255, 255, 255, 255}, 0, 0, 1},
+		{"gray", color.NRGBA{128, 128, 128, 255}, 0, 0, 0.502},
+		{"black", color.NRGBA{0, 0, 0, 255}, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		h, s, l := rgbToHSL(tt.c)
+		if math.Abs(h-tt.h) > 0.1 || math.Abs(s-tt.s) > 0.001 || math.Abs(l-tt.l) > 0.001 {
+			t.Errorf("%s: rgbToHSL(%v) = %.1f, %.3f, %.3f, want %.1f, %.3f, %.3f", tt.name, tt.c, h, s, l, tt.h, tt.s, tt.l)
+		}
+	}
+}
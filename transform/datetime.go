@@ -0,0 +1,82 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"clipbox/detect"
+)
+
+// isDate reports whether content is a date or timestamp that detect.ParseDateTime can read
+func isDate(content []byte) bool {
+	_, ok := detect.ParseDateTime(content)
+	return ok
+}
+
+// dateTimeTransforms convert dates and Unix timestamps, in local time where a zone matters
+var dateTimeTransforms = []Transform{
+	zonedDateTransform("date-iso", "ISO 8601 local date and time", "2006-01-02T15:04:05", time.Local),
+	zonedDateTransform("date-rfc3339", "RFC 3339 date and time with offset", time.RFC3339, time.Local),
+	{
+		Name:        "date-epoch",
+		Description: "Unix timestamp in seconds",
+		Match:       isDate,
+		Apply: dateTransform(func(t time.Time) string {
+			return strconv.FormatInt(t.Unix(), 10)
+		}),
+	},
+	{
+		Name:        "date-epoch-ms",
+		Description: "Unix timestamp in milliseconds",
+		Match:       isDate,
+		Apply: dateTransform(func(t time.Time) string {
+			return strconv.FormatInt(t.UnixMilli(), 10)
+		}),
+	},
+}
+
+// RegisterTimeZones adds date-iso:ZONE and date-rfc3339:ZONE transforms for each IANA time zone.
+// Zones that are already registered or unknown are skipped.
+func RegisterTimeZones(zones []string) {
+	for _, zone := range zones {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			continue
+		}
+		for _, t := range []Transform{
+			zonedDateTransform("date-iso:"+zone, "ISO 8601 date and time in "+zone, "2006-01-02T15:04:05", loc),
+			zonedDateTransform("date-rfc3339:"+zone, "RFC 3339 date and time in "+zone, time.RFC3339, loc),
+		} {
+			if _, ok := Get(t.Name); !ok {
+				Register(t)
+			}
+		}
+	}
+}
+
+// zonedDateTransform formats dates with layout in loc
+func zonedDateTransform(name, description, layout string, loc *time.Location) Transform {
+	return Transform{
+		Name:        name,
+		Description: description,
+		Match:       isDate,
+		Apply: dateTransform(func(t time.Time) string {
+			return t.In(loc).Format(layout)
+		}),
+	}
+}
+
+// dateTransform returns an Apply function that parses a date and formats it
+func dateTransform(format func(t time.Time) string) func(content []byte) ([]byte, error) {
+	return func(content []byte) ([]byte, error) {
+		t, ok := detect.ParseDateTime(content)
+		if !ok {
+			return nil, fmt.Errorf("not a date")
+		}
+		return []byte(format(t)), nil
+	}
+}
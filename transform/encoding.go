@@ -0,0 +1,148 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"clipbox/detect"
+	"clipbox/image"
+	"clipbox/utils"
+)
+
+// percentEscapeRegex matches a percent-encoded byte such as %20
+var percentEscapeRegex = regexp.MustCompile(`%[0-9A-Fa-f]{2}`)
+
+// base64Encodings are tried in order when decoding
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
+// encodingTransforms are the URL, base64 and text encoding transforms
+var encodingTransforms = []Transform{
+	{
+		Name:        "url-encode",
+		Description: "Percent-encode for use in a URL query",
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(url.QueryEscape(string(content))), nil
+		},
+	},
+	{
+		Name:        "url-decode",
+		Description: "Decode percent-encoded text",
+		Match: func(content []byte) bool {
+			return utf8.Valid(content) && percentEscapeRegex.Match(content)
+		},
+		Apply: func(content []byte) ([]byte, error) {
+			decoded, err := url.QueryUnescape(string(bytes.TrimSpace(content)))
+			if err != nil {
+				return nil, err
+			}
+			return []byte(decoded), nil
+		},
+	},
+	{
+		Name:        "base64-encode",
+		Description: "Encode as base64",
+		Match: func(content []byte) bool {
+			return true
+		},
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(base64.StdEncoding.EncodeToString(content)), nil
+		},
+	},
+	{
+		Name:        "base64-decode",
+		Description: "Decode base64",
+		Match:       isBase64,
+		Apply:       decodeBase64,
+	},
+	{
+		Name:        "to-utf8",
+		Description: "Convert UTF-16, Latin-1 or Cyrillic text to UTF-8",
+		Match: func(content []byte) bool {
+			_, encoding, ok := detect.DecodeText(content)
+			return ok && encoding != ""
+		},
+		Apply: func(content []byte) ([]byte, error) {
+			text, _, ok := detect.DecodeText(content)
+			if !ok {
+				return nil, errors.New("content is not text")
+			}
+			return []byte(text), nil
+		},
+	},
+	{
+		Name:        "crlf-to-lf",
+		Description: "Convert Windows CRLF line endings to LF",
+		Match: func(content []byte) bool {
+			return utf8.Valid(content) && bytes.Contains(content, []byte("\r\n"))
+		},
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(utils.NormalizeLineEndings(string(content))), nil
+		},
+	},
+}
+
+// minBase64Length is the shortest base64 offered for decoding, shorter words are rarely encoded data
+const minBase64Length = 8
+
+// isBase64 reports whether content is padded base64 of text or a known file format.
+// Apply also decodes unpadded base64, but the menu only offers decoding for likely encoded data,
+// not for words that happen to be valid base64.
+func isBase64(content []byte) bool {
+	text := string(bytes.TrimSpace(content))
+	if len(text) < minBase64Length || len(text)%4 != 0 {
+		return false
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+		if decoded, err := encoding.Strict().DecodeString(text); err == nil {
+			return isDecodedContent(decoded)
+		}
+	}
+	return false
+}
+
+// isDecodedContent reports whether decoded base64 is UTF-8 text without control characters
+// or an image or file format detect recognizes
+func isDecodedContent(decoded []byte) bool {
+	if _, ok := image.DetectImageFormat(decoded); ok {
+		return true
+	}
+	if _, ok := detect.DetectBinaryFormat(decoded); ok {
+		return true
+	}
+	if !utf8.Valid(decoded) {
+		return false
+	}
+	for _, r := range string(decoded) {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding.
+func decodeBase64(content []byte) ([]byte, error) {
+	text := string(bytes.TrimSpace(content))
+	if text == "" {
+		return nil, errors.New("empty input")
+	}
+	for _, encoding := range base64Encodings {
+		if decoded, err := encoding.DecodeString(text); err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, errors.New("not valid base64")
+}
@@ -0,0 +1,43 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestBase64DecodeMatch(t *testing.T) {
+	decode, ok := Get("base64-decode")
+	if !ok {
+		t.Fatal("base64-decode is not registered")
+	}
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"padded text", "aGVsbG8gd29ybGQ=", true},
+		{"text with two padding characters", "SGVsbG8sIFdvcmxkIQ==", true},
+		{"url-safe", "aGk_Pz8_", true},
+		{"pdf", base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\x00\x01\x02")), true},
+		{"surrounding whitespace", "  aGVsbG8gd29ybGQ=\n", true},
+		{"missing padding", "aGVsbG8gd29ybGQ", false},
+		{"wrong padding", "aGVsbG8gd29ybA===", false},
+		{"too short", "Zm9v", false},
+		{"word", "password", false},
+		{"binary garbage", "testtest", false},
+		{"sentence", "hello world", false},
+	}
+	for _, tt := range tests {
+		if got := decode.Match([]byte(tt.text)); got != tt.want {
+			t.Errorf("%s: base64-decode matches %q = %v, want %v", tt.name, tt.text, got, tt.want)
+		}
+	}
+
+	// Unpadded base64 is still decoded when chosen explicitly
+	if decoded, err := decode.Apply([]byte("aGVsbG8gd29ybGQ")); err != nil || string(decoded) != "hello world" {
+		t.Errorf("base64-decode of unpadded base64 = %q, %v, want hello world", decoded, err)
+	}
+}
@@ -0,0 +1,78 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"clipbox/detect"
+)
+
+// jsonTransforms are the JSON formatting and validation transforms
+var jsonTransforms = []Transform{
+	{
+		Name:        "json-pretty",
+		Description: "Pretty-print JSON",
+		Match:       detect.IsJSON,
+		Apply: func(content []byte) ([]byte, error) {
+			var out bytes.Buffer
+			if err := json.Indent(&out, bytes.TrimSpace(content), "", "  "); err != nil {
+				return nil, err
+			}
+			return out.Bytes(), nil
+		},
+	},
+	{
+		Name:        "json-minify",
+		Description: "Minify JSON",
+		Match:       detect.IsJSON,
+		Apply: func(content []byte) ([]byte, error) {
+			var out bytes.Buffer
+			if err := json.Compact(&out, bytes.TrimSpace(content)); err != nil {
+				return nil, err
+			}
+			return out.Bytes(), nil
+		},
+	},
+	{
+		Name:        "json-validate",
+		Description: "Check JSON syntax, copy it unchanged if valid",
+		Match:       looksLikeJSON,
+		Apply: func(content []byte) ([]byte, error) {
+			trimmed := bytes.TrimSpace(content)
+			var value any
+			if err := json.Unmarshal(trimmed, &value); err != nil {
+				var syntaxErr *json.SyntaxError
+				if errors.As(err, &syntaxErr) {
+					line, column := position(trimmed, syntaxErr.Offset)
+					return nil, fmt.Errorf("invalid JSON at line %d, column %d: %w", line, column, err)
+				}
+				return nil, fmt.Errorf("invalid JSON: %w", err)
+			}
+			return content, nil
+		},
+	},
+}
+
+// looksLikeJSON reports whether content is enclosed in braces or brackets, valid or not
+func looksLikeJSON(content []byte) bool {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) < 2 {
+		return false
+	}
+	first, last := trimmed[0], trimmed[len(trimmed)-1]
+	return (first == '{' && last == '}') || (first == '[' && last == ']')
+}
+
+// position returns the 1-based line and column of a byte offset in content
+func position(content []byte, offset int64) (int, int) {
+	offset = min(offset, int64(len(content)))
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,50 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"strings"
+
+	"clipbox/utils"
+)
+
+// textTransforms are the plain text transforms
+var textTransforms = []Transform{
+	{
+		Name:        "trim",
+		Description: "Remove leading and trailing whitespace",
+		Apply: func(content []byte) ([]byte, error) {
+			return bytes.TrimSpace(content), nil
+		},
+	},
+	{
+		Name:        "single-line",
+		Description: "Join lines and collapse whitespace",
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(strings.Join(strings.Fields(string(content)), " ")), nil
+		},
+	},
+	{
+		Name:        "upper",
+		Description: "Convert to upper case",
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(strings.ToUpper(string(content))), nil
+		},
+	},
+	{
+		Name:        "lower",
+		Description: "Convert to lower case",
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(strings.ToLower(string(content))), nil
+		},
+	},
+	{
+		Name:        "shell-quote",
+		Description: "Quote as a single shell argument",
+		Apply: func(content []byte) ([]byte, error) {
+			return []byte(utils.ShellQuote(string(content))), nil
+		},
+	},
+}
@@ -0,0 +1,84 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Transform is a named conversion of clipboard content.
+type Transform struct {
+	Name        string
+	Description string
+	// Match reports whether the transform applies to content. Nil means any text.
+	Match func(content []byte) bool
+	Apply func(content []byte) ([]byte, error)
+}
+
+// registry holds transforms in the order they are shown in menus
+var registry []Transform
+
+func init() {
+	for _, group := range [][]Transform{textTransforms, encodingTransforms, jsonTransforms, yamlTransforms, colorTransforms, dateTimeTransforms} {
+		for _, t := range group {
+			Register(t)
+		}
+	}
+}
+
+// Register adds a transform to the registry.
+// Panics if a transform with the same name is already registered.
+func Register(t Transform) {
+	if _, ok := Get(t.Name); ok {
+		panic(fmt.Sprintf("transform %q registered twice", t.Name))
+	}
+	registry = append(registry, t)
+}
+
+// All returns all registered transforms.
+func All() []Transform {
+	return registry
+}
+
+// Get returns the transform with the given name.
+func Get(name string) (Transform, bool) {
+	for _, t := range registry {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Transform{}, false
+}
+
+// Available returns the transforms that apply to content.
+func Available(content []byte) []Transform {
+	var result []Transform
+	for _, t := range registry {
+		if t.Matches(content) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Matches reports whether the transform applies to content.
+func (t Transform) Matches(content []byte) bool {
+	if t.Match != nil {
+		return t.Match(content)
+	}
+	return utf8.Valid(content)
+}
+
+// Apply runs the named transform on content.
+func Apply(name string, content []byte) ([]byte, error) {
+	t, ok := Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown transform: %s", name)
+	}
+	if !t.Matches(content) {
+		return nil, fmt.Errorf("transform %s does not apply to this entry", name)
+	}
+	return t.Apply(content)
+}
@@ -0,0 +1,100 @@
+package transform
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"clipbox/detect"
+)
+
+// yamlTransforms are the YAML formatting and validation transforms
+var yamlTransforms = []Transform{
+	{
+		Name:        "yaml-pretty",
+		Description: "Reformat YAML with 2-space indentation",
+		Match:       detect.IsYAML,
+		Apply: func(content []byte) ([]byte, error) {
+			return formatYAML(content, false)
+		},
+	},
+	{
+		Name:        "yaml-minify",
+		Description: "YAML on one line in flow style",
+		Match:       detect.IsYAML,
+		Apply: func(content []byte) ([]byte, error) {
+			out, err := formatYAML(content, true)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.TrimSpace(out), nil
+		},
+	},
+	{
+		Name:        "yaml-validate",
+		Description: "Check YAML syntax, copy it unchanged if valid",
+		Match:       looksLikeYAML,
+		Apply: func(content []byte) ([]byte, error) {
+			var doc yaml.Node
+			if err := yaml.Unmarshal(content, &doc); err != nil {
+				return nil, fmt.Errorf("invalid YAML: %w", err)
+			}
+			return content, nil
+		},
+	},
+}
+
+// yamlKeyRegex matches a block mapping key or list item at the start of a line
+var yamlKeyRegex = regexp.MustCompile(`(?m)^\s*(- |[\w.-]+:(\s|$))`)
+
+// looksLikeYAML reports whether content has several lines and YAML keys or list items, valid or not
+func looksLikeYAML(content []byte) bool {
+	trimmed := bytes.TrimSpace(content)
+	return bytes.Contains(trimmed, []byte("\n")) && yamlKeyRegex.Match(trimmed) && !looksLikeJSON(trimmed)
+}
+
+// formatYAML encodes the documents of content again with 2-space indentation.
+// If flow is true, collections are put on one line and comments are removed.
+func formatYAML(content []byte, flow bool) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if flow {
+			flatten(&doc)
+		}
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// flatten switches all collections under node to flow style and removes their comments
+func flatten(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		node.Style |= yaml.FlowStyle
+	}
+	node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+	for _, child := range node.Content {
+		flatten(child)
+	}
+}
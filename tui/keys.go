@@ -0,0 +1,129 @@
+package tui
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyBackspace
+	keyTab
+	keyBackTab
+	keyEscape
+	keyQuit
+	keyPin
+	keyDelete
+	keyClear
+	keyMove // Alt+digit, the digit is the target buffer
+	keyUnknown
+)
+
+type key struct {
+	kind keyKind
+	r    rune
+}
+
+// escapeSequences maps terminal escape sequences to keys
+var escapeSequences = map[string]keyKind{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1bOH":  keyHome,
+	"\x1b[1~": keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1bOF":  keyEnd,
+	"\x1b[4~": keyEnd,
+	"\x1b[Z":  keyBackTab,
+	"\x1b[3~": keyDelete,
+}
+
+// parseKeys splits a chunk read from the terminal into keys.
+// A lone ESC at the end of the chunk is the Escape key.
+func parseKeys(data []byte) []key {
+	var keys []key
+	s := string(data)
+	for len(s) > 0 {
+		if s[0] == 0x1b {
+			if len(s) == 1 {
+				keys = append(keys, key{kind: keyEscape})
+				break
+			}
+			if s[1] >= '1' && s[1] <= '9' {
+				keys = append(keys, key{kind: keyMove, r: rune(s[1])})
+				s = s[2:]
+				continue
+			}
+			matched := false
+			for seq, kind := range escapeSequences {
+				if strings.HasPrefix(s, seq) {
+					keys = append(keys, key{kind: kind})
+					s = s[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// Unknown sequence: skip ESC and the CSI/SS3 introducer with its parameters
+				end := 2
+				if s[1] == '[' || s[1] == 'O' {
+					for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+						end++
+					}
+					end++
+				}
+				keys = append(keys, key{kind: keyUnknown})
+				s = s[min(end, len(s)):]
+			}
+			continue
+		}
+
+		switch s[0] {
+		case '\r', '\n':
+			keys = append(keys, key{kind: keyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, key{kind: keyBackspace})
+		case '\t':
+			keys = append(keys, key{kind: keyTab})
+		case 0x03, 0x11: // Ctrl+C, Ctrl+Q
+			keys = append(keys, key{kind: keyQuit})
+		case 0x14: // Ctrl+T
+			keys = append(keys, key{kind: keyPin})
+		case 0x04: // Ctrl+D
+			keys = append(keys, key{kind: keyDelete})
+		case 0x15: // Ctrl+U
+			keys = append(keys, key{kind: keyClear})
+		case 0x10: // Ctrl+P
+			keys = append(keys, key{kind: keyUp})
+		case 0x0e: // Ctrl+N
+			keys = append(keys, key{kind: keyDown})
+		default:
+			if s[0] < 0x20 {
+				keys = append(keys, key{kind: keyUnknown})
+				break
+			}
+			r, size := utf8.DecodeRuneInString(s)
+			keys = append(keys, key{kind: keyRune, r: r})
+			s = s[size:]
+			continue
+		}
+		s = s[1:]
+	}
+	return keys
+}
@@ -0,0 +1,26 @@
+package tui
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import "golang.org/x/term"
+
+// makeRaw switches the terminal to raw mode and returns a function restoring the previous mode.
+func makeRaw(fd int) (func(), error) {
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = term.Restore(fd, old)
+	}, nil
+}
+
+// terminalSize returns the number of columns and rows of the terminal, 80x24 if unknown.
+func terminalSize(fd int) (int, int) {
+	width, height, err := term.GetSize(fd)
+	if err != nil || width == 0 || height == 0 {
+		return 80, 24
+	}
+	return width, height
+}
@@ -0,0 +1,423 @@
+package tui
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"clipbox/config"
+	"clipbox/database"
+	"clipbox/detect"
+	"clipbox/frontend"
+	"clipbox/preview"
+	"clipbox/utils"
+)
+
+// Backend is the subset of clipbox operations used by the terminal UI.
+type Backend interface {
+	Listing(limit int, app string) (*database.Listing, error)
+	Get(id int) (*database.Record, error)
+	TogglePin(id int) error
+	Move(id int, bufferID int) error
+	Delete(id int) error
+	SwitchToNextBuffer() error
+	SwitchToPreviousBuffer() error
+}
+
+const helpLine = "Enter copy  ^T pin  ^D delete  Alt+1-5 move  Tab/S-Tab buffer  ^U clear  Esc quit"
+
+// model is the state of the terminal UI
+type model struct {
+	b        Backend
+	cfg      *config.Config
+	listing  *database.Listing
+	rows     []database.Entry // entries matching the filter
+	filter   []rune
+	cursor   int
+	offset   int
+	status   string
+	previews map[int][]string // preview pane lines by entry ID
+}
+
+// Run shows the full-screen terminal UI on the terminal in and out.
+// Returns the ID of the entry chosen with Enter, or 0 if the UI was closed without a choice.
+func Run(b Backend, in *os.File, out *os.File) (int, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return 0, err
+	}
+
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return 0, errors.New("--tui requires a terminal")
+	}
+	defer restore()
+
+	// Alternate screen, hidden cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	m := &model{b: b, cfg: cfg, previews: map[int][]string{}}
+	if err := m.reload(); err != nil {
+		return 0, err
+	}
+
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	defer signal.Stop(resized)
+
+	input := make(chan []byte)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				close(input)
+				return
+			}
+			input <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	for {
+		width, height := terminalSize(int(out.Fd()))
+		m.render(out, width, height)
+
+		select {
+		case <-resized:
+		case data, ok := <-input:
+			if !ok {
+				return 0, nil
+			}
+			for _, k := range parseKeys(data) {
+				id, done := m.handle(k, height)
+				if done {
+					return id, nil
+				}
+			}
+		}
+	}
+}
+
+// reload reads the current buffer again and reapplies the filter.
+func (m *model) reload() error {
+	listing, err := m.b.Listing(0, "")
+	if err != nil {
+		return err
+	}
+	m.listing = listing
+	m.previews = map[int][]string{}
+	m.applyFilter()
+	return nil
+}
+
+// applyFilter keeps the rows containing every word of the filter, ignoring case.
+func (m *model) applyFilter() {
+	words := strings.Fields(strings.ToLower(string(m.filter)))
+	m.rows = m.rows[:0]
+	for _, e := range frontend.Rows(m.listing) {
+		text := strings.ToLower(e.Text)
+		matches := true
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			m.rows = append(m.rows, e)
+		}
+	}
+	m.cursor = max(0, min(m.cursor, len(m.rows)-1))
+}
+
+// selected returns the entry under the cursor.
+func (m *model) selected() (database.Entry, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return database.Entry{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// handle applies a key. Returns the chosen entry ID and true when the UI should close.
+func (m *model) handle(k key, height int) (int, bool) {
+	page := max(1, height-4)
+	m.status = ""
+
+	switch k.kind {
+	case keyEscape, keyQuit:
+		return 0, true
+	case keyEnter:
+		if e, ok := m.selected(); ok {
+			return e.ID, true
+		}
+	case keyUp:
+		m.cursor = max(0, m.cursor-1)
+	case keyDown:
+		m.cursor = max(0, min(len(m.rows)-1, m.cursor+1))
+	case keyPageUp:
+		m.cursor = max(0, m.cursor-page)
+	case keyPageDown:
+		m.cursor = max(0, min(len(m.rows)-1, m.cursor+page))
+	case keyHome:
+		m.cursor = 0
+	case keyEnd:
+		m.cursor = max(0, len(m.rows)-1)
+	case keyRune:
+		m.filter = append(m.filter, k.r)
+		m.cursor = 0
+		m.applyFilter()
+	case keyBackspace:
+		if len(m.filter) > 0 {
+			m.filter = m.filter[:len(m.filter)-1]
+			m.applyFilter()
+		}
+	case keyClear:
+		m.filter = nil
+		m.applyFilter()
+	case keyTab:
+		m.do(m.b.SwitchToNextBuffer())
+		m.cursor = 0
+	case keyBackTab:
+		m.do(m.b.SwitchToPreviousBuffer())
+		m.cursor = 0
+	case keyPin:
+		if e, ok := m.selected(); ok {
+			m.do(m.b.TogglePin(e.ID))
+		}
+	case keyDelete:
+		if e, ok := m.selected(); ok {
+			m.do(m.b.Delete(e.ID))
+		}
+	case keyMove:
+		bufferID := int(k.r - '0')
+		if e, ok := m.selected(); ok && bufferID >= 1 && bufferID <= 5 {
+			if bufferID == m.listing.Buffer {
+				m.status = fmt.Sprintf("Entry is already in buffer %d", bufferID)
+				break
+			}
+			m.do(m.b.Move(e.ID, bufferID))
+			if m.status == "" {
+				m.status = fmt.Sprintf("Moved to %s", m.bufferName(bufferID))
+			}
+		}
+	}
+	return 0, false
+}
+
+// do reloads the listing after an operation, or shows its error in the status line.
+func (m *model) do(err error) {
+	if err == nil {
+		err = m.reload()
+	}
+	if err != nil {
+		m.status = "Error: " + err.Error()
+	}
+}
+
+// bufferName returns the configured name of a buffer.
+func (m *model) bufferName(bufferID int) string {
+	if name := m.cfg.BufferNames[bufferID-1]; name != "" {
+		return name
+	}
+	return fmt.Sprintf("Buffer %d", bufferID)
+}
+
+// render draws the whole screen: buffer tabs, filter, list with preview pane and status line.
+func (m *model) render(w io.Writer, width int, height int) {
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+
+	// Buffer tabs
+	var tabs strings.Builder
+	for bufferID := 1; bufferID <= 5; bufferID++ {
+		tab := fmt.Sprintf(" %d %s ", bufferID, m.bufferName(bufferID))
+		if bufferID == m.listing.Buffer {
+			tabs.WriteString("\x1b[7m" + tab + "\x1b[0m ")
+		} else {
+			tabs.WriteString(tab + " ")
+		}
+	}
+	writeLine(&sb, tabs.String())
+
+	writeLine(&sb, fit(fmt.Sprintf("> %s  (%d/%d)", string(m.filter), len(m.rows), len(frontend.Rows(m.listing))), width))
+
+	bodyHeight := max(1, height-3)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+bodyHeight {
+		m.offset = m.cursor - bodyHeight + 1
+	}
+
+	listWidth, previewWidth := width, 0
+	if width >= 60 {
+		listWidth = width * 2 / 5
+		previewWidth = width - listWidth - 3
+	}
+
+	var previewLines []string
+	if e, ok := m.selected(); ok && previewWidth > 0 {
+		previewLines = wrap(m.preview(e.ID), previewWidth)
+	}
+
+	for i := 0; i < bodyHeight; i++ {
+		var row string
+		if index := m.offset + i; index < len(m.rows) {
+			e := m.rows[index]
+			pin := "  "
+			if e.Pinned {
+				pin = "* "
+			}
+			row = fit(pin+strings.TrimSpace(sanitize(e.Text)), listWidth)
+			if index == m.cursor {
+				row = "\x1b[7m" + row + "\x1b[0m"
+			}
+		} else {
+			row = strings.Repeat(" ", listWidth)
+		}
+		if previewWidth > 0 {
+			row += " │ "
+			if i < len(previewLines) {
+				row += previewLines[i]
+			}
+		}
+		writeLine(&sb, row)
+	}
+
+	status := m.status
+	if status == "" {
+		status = helpLine
+	}
+	sb.WriteString("\x1b[2m" + fit(status, width) + "\x1b[0m\x1b[K")
+
+	io.WriteString(w, sb.String())
+}
+
+// preview returns the preview pane lines for an entry, cached until the next reload.
+func (m *model) preview(id int) []string {
+	if lines, ok := m.previews[id]; ok {
+		return lines
+	}
+
+	var lines []string
+	record, err := m.b.Get(id)
+	if err != nil {
+		lines = []string{"Error: " + err.Error()}
+		m.previews[id] = lines
+		return lines
+	}
+
+	info := []string{fmt.Sprintf("#%d", record.ID), record.CreatedAt.Local().Format("2006-01-02 15:04"), record.Source}
+	if record.SourceApp != "" {
+		info = append(info, record.SourceApp)
+	}
+	if record.Pinned {
+		info = append(info, "pinned")
+	}
+	lines = append(lines, strings.Join(info, " • "), "")
+
+	content := record.Content
+	bufferCfg := m.cfg.ForBuffer(record.Buffer)
+	c := detect.NewClassifier(bufferCfg.PasswordIgnorePatterns, bufferCfg.SecretThreshold).Classify(content)
+	if c.Encoding != "" {
+		lines = append(lines, fmt.Sprintf("Encoding: %s, shown as UTF-8", c.Encoding), "")
+		content = detect.TextContent(content)
+	}
+	switch {
+	case len(content) == 0:
+	case c.Type == detect.TypeImage:
+		lines = append(lines, fmt.Sprintf("Image: %s", strings.ToUpper(c.ImageFormat)), fmt.Sprintf("Size: %s", utils.FormatSize(len(content))))
+	case c.Binary && c.Format.Name != "":
+		lines = append(lines, fmt.Sprintf("%s: %s", c.Format.Name, c.Format.MimeType), fmt.Sprintf("Size: %s", utils.FormatSize(len(content))))
+	case c.Binary:
+		lines = append(lines, fmt.Sprintf("Binary data: %s", utils.FormatSize(len(content))))
+	case c.Type == detect.TypeCredential:
+		lines = append(lines, preview.CredentialLabel(c.SecretKind), fmt.Sprintf("Size: %s", utils.FormatSize(len(content))))
+	case c.Type == detect.TypeFile:
+		for _, path := range c.Files {
+			size := "missing"
+			if info, err := os.Stat(path); err == nil {
+				size = utils.FormatSize(int(info.Size()))
+				if info.IsDir() {
+					size = "directory"
+				}
+			}
+			lines = append(lines, fmt.Sprintf("%s • %s", sanitize(path), size))
+		}
+	case bufferCfg.MaskPasswords > 0 && c.Type == detect.TypePassword:
+		lines = append(lines, fmt.Sprintf("Secret: %s", c.SecretKind), "", strings.Repeat(m.cfg.PasswordMaskChar, utf8.RuneCount(content)))
+	default:
+		if t, ok := detect.ParseDateTime(content); ok && c.Type == detect.TypeDateTime {
+			lines = append(lines, "Local time: "+t.Local().Format(preview.LocalTimeLayout), "UTC: "+t.UTC().Format(time.RFC3339), "")
+		}
+		if summary := preview.Summary(content, c); summary != "" {
+			lines = append(lines, summary, "")
+		}
+		// Minified JSON is shown indented
+		var indented bytes.Buffer
+		if c.Type == detect.TypeJSON && json.Indent(&indented, bytes.TrimSpace(content), "", "  ") == nil {
+			content = indented.Bytes()
+		}
+		for _, line := range strings.Split(utils.NormalizeLineEndings(string(content)), "\n") {
+			lines = append(lines, sanitize(line))
+		}
+	}
+
+	m.previews[id] = lines
+	return lines
+}
+
+// writeLine writes a screen line, clearing the rest of it
+func writeLine(sb *strings.Builder, line string) {
+	sb.WriteString(line)
+	sb.WriteString("\x1b[K\r\n")
+}
+
+// sanitize expands tabs and replaces control characters, so content can't move the cursor
+func sanitize(text string) string {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// fit truncates text to width runes and pads it with spaces
+func fit(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:max(0, width)])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
+
+// wrap splits lines longer than width runes
+func wrap(lines []string, width int) []string {
+	var wrapped []string
+	for _, line := range lines {
+		runes := []rune(line)
+		for len(runes) > width {
+			wrapped = append(wrapped, string(runes[:width]))
+			runes = runes[width:]
+		}
+		wrapped = append(wrapped, string(runes))
+	}
+	return wrapped
+}
@@ -0,0 +1,260 @@
+//go:build linux
+
+package tui
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"clipbox/database"
+)
+
+// fakeBackend keeps entries in memory, newest first
+type fakeBackend struct {
+	mu      sync.Mutex
+	entries []database.Entry
+}
+
+func newFakeBackend(texts ...string) *fakeBackend {
+	b := &fakeBackend{}
+	for i, text := range texts {
+		b.entries = append(b.entries, database.Entry{ID: len(texts) - i, Text: text})
+	}
+	return b
+}
+
+func (b *fakeBackend) Listing(limit int, app string) (*database.Listing, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	listing := &database.Listing{Buffer: 1}
+	for _, e := range b.entries {
+		listing.Entries = append(listing.Entries, e)
+		if e.Pinned {
+			listing.Pinned = append(listing.Pinned, e)
+		}
+	}
+	return listing, nil
+}
+
+func (b *fakeBackend) Get(id int) (*database.Record, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range b.entries {
+		if e.ID == id {
+			return &database.Record{ID: e.ID, Buffer: 1, Pinned: e.Pinned, Content: []byte(e.Text), Source: "clipboard"}, nil
+		}
+	}
+	return nil, fmt.Errorf("entry %d not found", id)
+}
+
+func (b *fakeBackend) TogglePin(id int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.entries {
+		if b.entries[i].ID == id {
+			b.entries[i].Pinned = !b.entries[i].Pinned
+			return nil
+		}
+	}
+	return fmt.Errorf("entry %d not found", id)
+}
+
+func (b *fakeBackend) Delete(id int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := range b.entries {
+		if b.entries[i].ID == id {
+			b.entries = append(b.entries[:i], b.entries[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("entry %d not found", id)
+}
+
+func (b *fakeBackend) Move(id int, bufferID int) error { return errors.New("not supported") }
+func (b *fakeBackend) SwitchToNextBuffer() error       { return nil }
+func (b *fakeBackend) SwitchToPreviousBuffer() error   { return nil }
+
+// screenEnd is the end of the help line, the last line drawn by render
+const screenEnd = "Esc quit"
+
+// session is the terminal UI running on a pseudo-terminal
+type session struct {
+	t      *testing.T
+	master *os.File
+	result chan int
+
+	mu     sync.Mutex
+	output bytes.Buffer
+}
+
+// openPTY opens a pseudo-terminal and returns its master and slave sides.
+func openPTY(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminals are not available: %v", err)
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		t.Skipf("failed to unlock pseudo-terminal: %v", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		t.Skipf("failed to get pseudo-terminal number: %v", err)
+	}
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		t.Skipf("failed to open pseudo-terminal: %v", err)
+	}
+	if err := unix.IoctlSetWinsize(int(slave.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: 20, Col: 100}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		slave.Close()
+		master.Close()
+	})
+	return master, slave
+}
+
+// startSession runs the terminal UI on a pseudo-terminal and waits for the first screen.
+func startSession(t *testing.T, b Backend) *session {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	master, slave := openPTY(t)
+
+	s := &session{t: t, master: master, result: make(chan int, 1)}
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := master.Read(buf)
+			s.mu.Lock()
+			s.output.Write(buf[:n])
+			s.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		id, err := Run(b, slave, slave)
+		if err != nil {
+			t.Errorf("Run: %v", err)
+		}
+		s.result <- id
+	}()
+
+	s.waitFor(screenEnd)
+	return s
+}
+
+// send types keys, clearing the output of the previous ones
+func (s *session) send(keys string) {
+	s.t.Helper()
+	s.mu.Lock()
+	s.output.Reset()
+	s.mu.Unlock()
+	if _, err := s.master.WriteString(keys); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+// waitFor waits until the terminal output since the last keys contains text
+func (s *session) waitFor(text string) {
+	s.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		found := strings.Contains(s.output.String(), text)
+		s.mu.Unlock()
+		if found {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.t.Fatalf("terminal output does not contain %q:\n%q", text, s.output.String())
+}
+
+// wait returns the entry ID the terminal UI closed with
+func (s *session) wait() int {
+	s.t.Helper()
+	select {
+	case id := <-s.result:
+		s.waitFor("\x1b[?1049l")
+		return id
+	case <-time.After(5 * time.Second):
+		s.t.Fatal("terminal UI did not close")
+		return 0
+	}
+}
+
+func TestRunSelect(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want int
+	}{
+		{"first entry", []string{"\r"}, 3},
+		{"down", []string{"\x1b[B", "\r"}, 2},
+		{"down and up", []string{"\x1b[B\x1b[B", "\x1b[A", "\r"}, 2},
+		{"ctrl+n", []string{"\x0e\x0e", "\r"}, 1},
+		{"past the last entry", []string{"\x1b[B\x1b[B\x1b[B\x1b[B", "\r"}, 1},
+		{"end and home", []string{"\x1b[F", "\x1b[H", "\r"}, 3},
+		{"page down", []string{"\x1b[6~", "\r"}, 1},
+		{"filter", []string{"ALP", "\r"}, 1},
+		{"filter and backspace", []string{"alx", "\x7f", "\r"}, 1},
+		{"filter without match", []string{"zzz", "\r", "\x1b"}, 0},
+		{"escape", []string{"\x1b"}, 0},
+		{"ctrl+c", []string{"\x03"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := startSession(t, newFakeBackend("gamma", "beta", "alpha"))
+			for _, keys := range tt.keys {
+				s.send(keys)
+				if keys != "\r" && keys != "\x1b" && keys != "\x03" {
+					s.waitFor(screenEnd)
+				}
+			}
+			if got := s.wait(); got != tt.want {
+				t.Errorf("selected entry %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunPinAndDelete(t *testing.T) {
+	b := newFakeBackend("gamma", "beta", "alpha")
+	s := startSession(t, b)
+
+	s.send("\x1b[B\x14")
+	s.waitFor("* beta")
+
+	s.send("\x04")
+	s.waitFor("(2/2)")
+
+	s.send("\r")
+	// The cursor stays on the row after the deleted entry
+	if got := s.wait(); got != 1 {
+		t.Errorf("selected entry %d after deleting, want 1", got)
+	}
+	if len(b.entries) != 2 || b.entries[0].ID != 3 || b.entries[1].ID != 1 {
+		t.Errorf("entries after deleting = %+v, want 3 and 1", b.entries)
+	}
+}
@@ -5,20 +5,47 @@ package utils
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
-// CopyToClipboard copies content to the Wayland clipboard using wl-copy
-func CopyToClipboard(content []byte) error {
-	cmd := exec.Command("wl-copy")
+// Selections an entry can be captured from
+const (
+	SourceClipboard = "clipboard"
+	SourcePrimary   = "primary"
+)
+
+// CopyToClipboard copies content to the Wayland clipboard using wl-copy.
+// If primary is true, content is also copied to the primary selection.
+func CopyToClipboard(content []byte, primary bool) error {
+	return CopyToClipboardType(content, "", primary)
+}
+
+// CopyToClipboardType copies content to the Wayland clipboard offered as mimeType.
+// An empty mimeType lets wl-copy detect the type.
+func CopyToClipboardType(content []byte, mimeType string, primary bool) error {
+	var typeArgs []string
+	if mimeType != "" {
+		typeArgs = []string{"--type", mimeType}
+	}
+	cmd := exec.Command("wl-copy", typeArgs...)
 	cmd.Stdin = bytes.NewReader(content)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to copy to clipboard: %w", err)
 	}
+	if primary {
+		cmd := exec.Command("wl-copy", append([]string{"--primary"}, typeArgs...)...)
+		cmd.Stdin = bytes.NewReader(content)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to copy to primary selection: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -37,6 +64,20 @@ func ExtractID(input string) (int, error) {
 	return 0, fmt.Errorf("input not prefixed with id and ROFI_INFO not set")
 }
 
+// ExtractIDs gets entry IDs from several selected rows, e.g. the output of rofi -dmenu -multi-select.
+// Falls back to ExtractID for a single row without hidden encoding. Duplicate IDs are removed.
+func ExtractIDs(input string) ([]int, error) {
+	ids := DecodeIDsHidden(input)
+	if len(ids) == 0 {
+		id, err := ExtractID(input)
+		if err != nil {
+			return nil, err
+		}
+		return []int{id}, nil
+	}
+	return ids, nil
+}
+
 // EncodeIDHidden encodes an ID as invisible Unicode characters.
 // Uses variation selectors (U+FE00-U+FE09) surrounded by zero-width spaces (U+200B).
 func EncodeIDHidden(id int) string {
@@ -53,20 +94,35 @@ func EncodeIDHidden(id int) string {
 	return result.String()
 }
 
-// DecodeIDHidden extracts an ID encoded by EncodeIDHidden from input string
-func DecodeIDHidden(input string) (int, bool) {
-	zwsp := "\u200b"
-	startIdx := strings.Index(input, zwsp)
-	if startIdx == -1 {
+// hiddenIDRegex matches an ID encoded by EncodeIDHidden at the end of a row
+var hiddenIDRegex = regexp.MustCompile(`\x{200b}([\x{fe00}-\x{fe09}]+)\x{200b}$`)
+
+// DecodeIDHidden extracts the ID encoded by EncodeIDHidden at the end of a row.
+// Zero-width spaces in the text of the row are ignored, only trailing whitespace may follow the ID.
+func DecodeIDHidden(row string) (int, bool) {
+	match := hiddenIDRegex.FindStringSubmatch(strings.TrimRightFunc(row, unicode.IsSpace))
+	if match == nil {
 		return 0, false
 	}
-	afterStart := input[startIdx+len(zwsp):]
-	endIdx := strings.Index(afterStart, zwsp)
-	if endIdx == -1 {
-		return 0, false
+	return decodeDigitsHidden(match[1])
+}
+
+// DecodeIDsHidden extracts the IDs encoded by EncodeIDHidden at the end of each line of input, in order.
+// Duplicate IDs are returned once.
+func DecodeIDsHidden(input string) []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, line := range strings.Split(input, "\n") {
+		if id, ok := DecodeIDHidden(line); ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
 	}
-	encoded := afterStart[:endIdx]
+	return ids
+}
 
+// decodeDigitsHidden converts variation selectors back to a positive ID
+func decodeDigitsHidden(encoded string) (int, bool) {
 	var digits strings.Builder
 	for _, r := range encoded {
 		if r >= 0xFE00 && r <= 0xFE09 {
@@ -83,3 +139,57 @@ func DecodeIDHidden(input string) (int, bool) {
 	}
 	return id, true
 }
+
+// ReadClipboard returns the current content of the Wayland clipboard using wl-paste.
+// An empty clipboard is returned as empty content, not as an error.
+func ReadClipboard() ([]byte, error) {
+	// wl-paste --list-types prints no types, or exits with an error, when nothing is copied
+	types, err := exec.Command("wl-paste", "--list-types").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	if len(bytes.TrimSpace(types)) == 0 {
+		return nil, nil
+	}
+
+	output, err := exec.Command("wl-paste", "--no-newline").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	return output, nil
+}
+
+// SelectionOffers reports whether the clipboard, or the primary selection if primary is true,
+// offers content as mimeType. Returns false if wl-paste fails.
+func SelectionOffers(mimeType string, primary bool) bool {
+	args := []string{"--list-types"}
+	if primary {
+		args = append(args, "--primary")
+	}
+	types, err := exec.Command("wl-paste", args...).Output()
+	if err != nil {
+		return false
+	}
+	for _, offered := range strings.Split(string(types), "\n") {
+		if strings.TrimSpace(offered) == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// Prompt asks for a line of text using rofi -dmenu.
+// Returns an error if the prompt is cancelled.
+func Prompt(label string) (string, error) {
+	cmd := exec.Command("rofi", "-dmenu", "-p", label, "-l", "0")
+	cmd.Stdin = strings.NewReader("")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("prompt %q cancelled", label)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
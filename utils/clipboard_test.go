@@ -0,0 +1,82 @@
+package utils
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDecodeIDHidden(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+		want int
+		ok   bool
+	}{
+		{"plain row", "hello" + EncodeIDHidden(42), 42, true},
+		{"trailing newline", "hello" + EncodeIDHidden(7) + "\n", 7, true},
+		{"zero-width space in text", "a\u200bb\u200bc" + EncodeIDHidden(12), 12, true},
+		{"lone zero-width space in text", "a\u200b" + EncodeIDHidden(3), 3, true},
+		{"encoded id in text", "copied" + EncodeIDHidden(99) + " row" + EncodeIDHidden(5), 5, true},
+		{"variation selectors in text", "\u200b\ufe01\u200b text", 0, false},
+		{"no id", "hello", 0, false},
+		{"empty", "", 0, false},
+		{"id zero", "hello\u200b\ufe00\u200b", 0, false},
+	}
+	for _, tt := range tests {
+		id, ok := DecodeIDHidden(tt.row)
+		if id != tt.want || ok != tt.ok {
+			t.Errorf("%s: DecodeIDHidden(%q) = %d, %v, want %d, %v", tt.name, tt.row, id, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDecodeIDsHidden(t *testing.T) {
+	input := "one\u200b" + EncodeIDHidden(1) + "\n" +
+		"t\u200bw\u200bo" + EncodeIDHidden(2) + "\n" +
+		"no id\u200b\n" +
+		"one again" + EncodeIDHidden(1) + "\n" +
+		"three" + EncodeIDHidden(3) + "\n"
+	if got, want := DecodeIDsHidden(input), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("DecodeIDsHidden = %v, want %v", got, want)
+	}
+}
+
+func TestReadClipboard(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    string
+		wantErr bool
+	}{
+		{"content", `[ "$1" = --list-types ] && echo text/plain; [ "$1" = --no-newline ] && printf copied; exit 0`, "copied", false},
+		{"nothing copied", `echo "localized message" >&2; exit 1`, "", false},
+		{"no types", `exit 0`, "", false},
+		{"read fails", `[ "$1" = --list-types ] && echo text/plain && exit 0; exit 1`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			script := "#!/bin/sh\n" + tt.script + "\n"
+			if err := os.WriteFile(filepath.Join(dir, "wl-paste"), []byte(script), 0755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", dir)
+			got, err := ReadClipboard()
+			if (err != nil) != tt.wantErr || string(got) != tt.want {
+				t.Errorf("ReadClipboard() = %q, %v, want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing wl-paste", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		if _, err := ReadClipboard(); err == nil {
+			t.Error("ReadClipboard() without wl-paste succeeded, want an error")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package utils
+
+// Copyright (C) 2025 Maxim Kim (exynil)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RunCommand runs a shell command with stdin as its input and returns its standard output.
+// The command is killed when timeout expires.
+func RunCommand(command string, stdin []byte, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(stdin)
+	// Don't wait for background children holding stdout after the kill
+	cmd.WaitDelay = 100 * time.Millisecond
+
+	output, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("command timed out after %s: %s", timeout, command)
+	}
+	if err != nil {
+		return output, err
+	}
+	return output, nil
+}
+
+// StartCommand starts a shell command in the background with stdin as its input.
+// The command runs in its own session, so it outlives clipbox, and its output is discarded.
+func StartCommand(command string, stdin []byte) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	stdinPipe, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+	// The input is written before returning, clipbox may exit right after
+	_, writeErr := stdinPipe.Write(stdin)
+	stdinPipe.Close()
+	// Reap the process when clipbox keeps running, as the server does
+	go cmd.Wait()
+	if writeErr != nil {
+		return fmt.Errorf("failed to write command input: %w", writeErr)
+	}
+	return nil
+}
+
+// ShellQuote wraps text in single quotes for sh, escaping embedded single quotes.
+func ShellQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
@@ -5,6 +5,7 @@ package utils
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -15,6 +16,26 @@ var PangoReplacer = strings.NewReplacer(
 	">", "&gt;",
 )
 
+// pangoTagRegex matches Pango markup tags such as <span color='red'> and </span>
+var pangoTagRegex = regexp.MustCompile(`</?[a-zA-Z]+(\s[^<>]*)?>`)
+
+// pangoUnescaper reverses PangoReplacer
+var pangoUnescaper = strings.NewReplacer(
+	"&lt;", "<",
+	"&gt;", ">",
+	"&amp;", "&",
+)
+
+// StripMarkup converts Pango markup to plain text
+func StripMarkup(markup string) string {
+	return pangoUnescaper.Replace(pangoTagRegex.ReplaceAllString(markup, ""))
+}
+
+// NormalizeLineEndings converts Windows CRLF line endings to LF
+func NormalizeLineEndings(text string) string {
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
+
 // Trunc truncates a string to max runes and appends ellipsis if truncated
 func Trunc(in string, max int, ellip string) string {
 	runes := []rune(in)
@@ -24,8 +45,12 @@ func Trunc(in string, max int, ellip string) string {
 	return in
 }
 
-// FormatSize converts bytes to human-readable format (B, KiB, MiB, GiB)
+// FormatSize converts bytes to human-readable format (B, KiB, MiB, GiB).
+// Sizes below 1 KiB are whole bytes, such as "29 B".
 func FormatSize(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
 	units := []string{"B", "KiB", "MiB", "GiB"}
 	var i int
 	fsize := float64(size)